@@ -0,0 +1,96 @@
+package font
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// rotateSurface returns a copy of a 32-bit surface rotated clockwise by angle
+// radians around its center, using nearest neighbour sampling so pixel art
+// stays crisp. The result is just big enough to hold the rotated image.
+func rotateSurface(src *sdl.Surface, angle float64) *sdl.Surface {
+	sin, cos := math.Sincos(angle)
+	w, h := float64(src.W), float64(src.H)
+	dstW := int(math.Ceil(math.Abs(w*cos) + math.Abs(h*sin)))
+	dstH := int(math.Ceil(math.Abs(w*sin) + math.Abs(h*cos)))
+	dst := newSurface(dstW, dstH)
+
+	src.Lock()
+	dst.Lock()
+	srcPixels, dstPixels := src.Pixels(), dst.Pixels()
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			// map the center of each destination pixel back onto the source
+			dx := float64(x) + 0.5 - float64(dstW)/2
+			dy := float64(y) + 0.5 - float64(dstH)/2
+			sx := int(math.Floor(dx*cos + dy*sin + w/2))
+			sy := int(math.Floor(-dx*sin + dy*cos + h/2))
+			if sx < 0 || sy < 0 || sx >= int(src.W) || sy >= int(src.H) {
+				continue
+			}
+			from := sy*int(src.Pitch) + sx*4
+			to := y*int(dst.Pitch) + x*4
+			copy(dstPixels[to:to+4], srcPixels[from:from+4])
+		}
+	}
+	dst.Unlock()
+	src.Unlock()
+
+	return dst
+}
+
+// RenderStringArc draws text along a circle of the given radius, each glyph
+// rotated so its top faces outward. Angles are in radians, clockwise from the
+// positive X axis (so -π/2 is the top of the circle). The text is centered
+// within the arc of arcSpan radians starting at startAngle, or starts at
+// startAngle when arcSpan is 0. The surface covers the whole circle plus
+// room for the glyphs, with the circle's center in the middle.
+func (font *Font) RenderStringArc(text string, radius int, startAngle, arcSpan float64, r, g, b float64) *sdl.Surface {
+	pad := int(math.Ceil(math.Hypot(float64(font.CharSize[0]), float64(font.CharSize[1])) / 2))
+	center := radius + pad
+	surface := font.outputSurface(2*center, 2*center)
+	if radius <= 0 {
+		return surface
+	}
+
+	angle := startAngle
+	if arcSpan != 0 {
+		angle += (arcSpan - float64(font.getStringLen(text))/float64(radius)) / 2
+	}
+
+	tint := sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+	glyphs, _ := font.shape([]rune(font.normalize(font.substitute(text))), true)
+	for _, shaped := range glyphs {
+		char := shaped.Glyph
+		if char == ligatureTail {
+			continue
+		}
+		adv := font.shapedAdvance(shaped)
+		if _, ok := font.advance(char); !ok || char == '\t' {
+			angle += float64(adv) / float64(radius)
+			continue
+		}
+
+		// the angle of the middle of the glyph's advance
+		mid := angle + float64(adv)/2/float64(radius)
+		angle += float64(adv) / float64(radius)
+
+		src, ok := font.loadGlyph(char)
+		if !ok || src.W == 0 {
+			continue
+		}
+		top := int32(font.glyphTop(char))
+		glyph := newSurface(int(src.W), int(top+src.H))
+		enlarge(font.Atlas, src, glyph, 0, int(top), 1, tint) // copy the glyph as is before rotating it
+		rotated := rotateSurface(glyph, mid+math.Pi/2)
+		glyph.Free()
+
+		x := float64(center) + float64(radius)*math.Cos(mid) - float64(rotated.W)/2
+		y := float64(center) + float64(radius)*math.Sin(mid) - float64(rotated.H)/2
+		rotated.Blit(nil, surface, &sdl.Rect{X: int32(math.Round(x)), Y: int32(math.Round(y))})
+		rotated.Free()
+	}
+
+	return surface
+}
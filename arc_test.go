@@ -0,0 +1,30 @@
+package font
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRenderStringArcSubstitutes(t *testing.T) {
+	font := MakeDefaultFont()
+	font.Shortcodes = map[string]rune{"smile": '☺'}
+	font.CollapseWhitespace = true
+	for _, test := range []struct{ text, drawn string }{
+		{"hi :smile:", "hi ☺"},
+		{"a   b\t c", "a b c"},
+	} {
+		got := font.RenderStringArc(test.text, 40, -math.Pi, math.Pi, 1, 1, 1)
+		want := font.RenderStringArc(test.drawn, 40, -math.Pi, math.Pi, 1, 1, 1)
+		assertSamePixels(t, test.text, got, want)
+	}
+}
+
+func TestRenderStringArcLeavesAtlasAlone(t *testing.T) {
+	font := MakeDefaultFont()
+	font.Atlas.SetColorMod(10, 20, 30)
+	surface := font.RenderStringArc("Hi", 40, -math.Pi, math.Pi, 1, 0.5, 0)
+	assertColorMod(t, "RenderStringArc", font.Atlas, 10, 20, 30)
+	if c := inkColor(surface); c != [4]uint8{255, 127, 0, 255} {
+		t.Errorf("the arc's ink is %v, want the tint", c)
+	}
+}
@@ -0,0 +1,182 @@
+package font
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// AsepriteOptions configures LoadAseprite
+type AsepriteOptions struct {
+	// Baseline is the row of the sprite's canvas that glyphs sit on, and
+	// Ascent is how many rows above it the line starts. Canvas rows above the
+	// line are cut off. Both 0 keeps the whole canvas.
+	Baseline int
+	Ascent   int
+}
+
+type asepriteSheet struct {
+	Frames json.RawMessage `json:"frames"` // an object keyed by name, or an array
+	Meta   struct {
+		Image     string `json:"image"`
+		FrameTags []struct {
+			Name string `json:"name"`
+			From int    `json:"from"`
+		} `json:"frameTags"`
+		Slices []struct {
+			Name string `json:"name"`
+			Keys []struct {
+				Frame  int        `json:"frame"`
+				Bounds packerRect `json:"bounds"`
+			} `json:"keys"`
+		} `json:"slices"`
+	} `json:"meta"`
+}
+
+// orderedFrames decodes the frames of a sheet in the order they were
+// exported, which tags refer to them by, for both the hash and array formats
+func orderedFrames(data json.RawMessage) ([]packerFrame, error) {
+	var frames []packerFrame
+	if err := json.Unmarshal(data, &frames); err == nil {
+		return frames, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("frames are neither a hash nor an array")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var frame packerFrame
+		if err := decoder.Decode(&frame); err != nil {
+			return nil, err
+		}
+		frame.Filename = token.(string)
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// LoadAseprite creates a Font from a sheet exported by Aseprite with its JSON
+// data, in either the hash or the array format. Each frame tag or slice named
+// after a character, like "A" or "U+0041", is a glyph: a tag is its first
+// frame, a slice is its bounds in the frame its first key is on. Glyphs are as
+// wide as their frame or slice, so export with trimming for a proportional
+// font. Tags and slices that aren't characters are skipped and logged.
+//
+// The charset is ordered by code point, and lines are as tall as the canvas
+// below the line start set by opts.
+func LoadAseprite(path string, opts AsepriteOptions) (Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Font{}, err
+	}
+	var sheet asepriteSheet
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		return Font{}, fmt.Errorf("aseprite sheet %s: %w", path, err)
+	}
+	frames, err := orderedFrames(sheet.Frames)
+	if err != nil {
+		return Font{}, fmt.Errorf("aseprite sheet %s: %w", path, err)
+	}
+	lineTop := opts.Baseline - opts.Ascent
+
+	type spriteGlyph struct {
+		char   rune
+		source GlyphSource
+	}
+	var glyphs []spriteGlyph
+	var skipped []string
+	canvasHeight := 0
+
+	add := func(name string, frameIndex int, bounds *packerRect) error {
+		char, err := glyphRune(name)
+		if err != nil {
+			skipped = append(skipped, name)
+			return nil
+		}
+		if frameIndex < 0 || frameIndex >= len(frames) {
+			return fmt.Errorf("%q is on frame %d of %d", name, frameIndex, len(frames))
+		}
+		frame := frames[frameIndex]
+		if frame.Rotated {
+			return fmt.Errorf("%q is on a rotated frame, which isn't supported", name)
+		}
+		canvasHeight = max(canvasHeight, int(frame.SourceSize.H))
+
+		// the frame's trimmed pixels, positioned on the canvas
+		rect := sdl.Rect{X: frame.Frame.X, Y: frame.Frame.Y, W: frame.Frame.W, H: frame.Frame.H}
+		canvasX, canvasY := frame.SpriteSourceSize.X, frame.SpriteSourceSize.Y
+		if bounds != nil {
+			rect.X += bounds.X - canvasX
+			rect.Y += bounds.Y - canvasY
+			rect.W, rect.H = bounds.W, bounds.H
+			canvasY = bounds.Y
+		}
+		glyphs = append(glyphs, spriteGlyph{char, GlyphSource{Rect: rect, Top: int(canvasY) - lineTop}})
+		return nil
+	}
+
+	for _, tag := range sheet.Meta.FrameTags {
+		if err := add(tag.Name, tag.From, nil); err != nil {
+			return Font{}, fmt.Errorf("aseprite sheet %s: tag %w", path, err)
+		}
+	}
+	for _, slice := range sheet.Meta.Slices {
+		if len(slice.Keys) == 0 {
+			continue
+		}
+		if err := add(slice.Name, slice.Keys[0].Frame, &slice.Keys[0].Bounds); err != nil {
+			return Font{}, fmt.Errorf("aseprite sheet %s: slice %w", path, err)
+		}
+	}
+	if len(skipped) > 0 {
+		log.Printf("aseprite sheet %s: skipped tags and slices that aren't characters: %q", path, skipped)
+	}
+	if len(glyphs) == 0 {
+		return Font{}, fmt.Errorf("aseprite sheet %s: no tags or slices are named after characters", path)
+	}
+	slices.SortFunc(glyphs, func(a, b spriteGlyph) int { return int(a.char - b.char) })
+	for i := 1; i < len(glyphs); i++ {
+		if glyphs[i].char == glyphs[i-1].char {
+			return Font{}, fmt.Errorf("aseprite sheet %s: more than one tag or slice for %q", path, glyphs[i].char)
+		}
+	}
+
+	atlas, err := img.Load(filepath.Join(filepath.Dir(path), sheet.Meta.Image))
+	if err != nil {
+		return Font{}, err
+	}
+
+	var charSet strings.Builder
+	font := fontFromAtlas(atlas, 1, "", nil)
+	font.CharSize = [2]int{0, canvasHeight - lineTop}
+	font.Rects = make([]GlyphSource, len(glyphs))
+	for i, glyph := range glyphs {
+		// rows above the line are cut off the glyph
+		source := glyph.source
+		if source.Top < 0 {
+			source.Rect.Y -= int32(source.Top)
+			source.Rect.H = max(source.Rect.H+int32(source.Top), 0)
+			source.Top = 0
+		}
+		source.Advance = int(source.Rect.W) + font.LetterPad
+
+		charSet.WriteRune(glyph.char)
+		font.Rects[i] = source
+		font.CharSize[0] = max(font.CharSize[0], int(source.Rect.W))
+	}
+	font.CharSet = charSet.String()
+	return font, nil
+}
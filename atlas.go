@@ -0,0 +1,354 @@
+package font
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// copyPixels copies src onto dst exactly, alpha included, instead of blending it
+func copyPixels(src *sdl.Surface, srcRect *sdl.Rect, dst *sdl.Surface, dstRect *sdl.Rect) {
+	mode, _ := src.GetBlendMode()
+	src.SetBlendMode(sdl.BLENDMODE_NONE)
+	src.Blit(srcRect, dst, dstRect)
+	src.SetBlendMode(mode)
+}
+
+// cell returns the grid cell the glyph at index starts in, or the next free
+// cell for the index past the end of the charset. DoubleWidth glyphs take up
+// two cells and never straddle rows, so one that would start in the last
+// column starts the next row instead, leaving that cell empty.
+func (font *Font) cell(index int) int {
+	if len(font.DoubleWidth) == 0 {
+		return index
+	}
+	cell, i := 0, 0
+	for _, char := range font.CharSet {
+		wide := font.DoubleWidth[char]
+		if wide && cell%font.GridWidth == font.GridWidth-1 {
+			cell++
+		}
+		if i == index {
+			return cell
+		}
+		cell++
+		if wide {
+			cell++
+		}
+		i++
+	}
+	return cell
+}
+
+// cellOrigin returns the top left corner of a cell in the atlas grid
+func (font *Font) cellOrigin(index int) (x, y int) {
+	x = (index % font.GridWidth) * (font.CharSize[0] + font.cellPad())
+	y = (index / font.GridWidth) * (font.CharSize[1] + font.cellPad())
+	return
+}
+
+// AddGlyph appends a glyph to the font at runtime, copying the atlas with
+// its pixels in the next free cell, grown if that cell falls outside it.
+// The glyph's width becomes its CharWidths entry. The old atlas is left as
+// it was and isn't freed, since copies of the font may still draw with it.
+// It panics if the font already has char or the glyph doesn't fit in a cell.
+// Fonts with a rect table take glyphs of any size, stacked below the rest of
+// the atlas.
+func (font *Font) AddGlyph(char rune, glyph *sdl.Surface) {
+	if font.glyphIndex(char) >= 0 {
+		panic(fmt.Sprintf("AddGlyph: font already has %q", char))
+	}
+	if font.Rects != nil {
+		font.addRect(char, glyph)
+		return
+	}
+	if int(glyph.W) > font.CharSize[0] || int(glyph.H) > font.CharSize[1] {
+		panic(fmt.Sprintf("AddGlyph: %q is %dx%d, bigger than the %dx%d cell", char, glyph.W, glyph.H, font.CharSize[0], font.CharSize[1]))
+	}
+
+	cell := font.cell(utf8.RuneCountInString(font.CharSet))
+	x, y := font.cellOrigin(cell)
+
+	atlas := newSurface(
+		max(int(font.Atlas.W), font.GridWidth*(font.CharSize[0]+font.cellPad())-font.cellPad()),
+		max(int(font.Atlas.H), y+font.CharSize[1]),
+	)
+	copyPixels(font.Atlas, nil, atlas, &sdl.Rect{})
+	rect := sdl.Rect{X: int32(x), Y: int32(y), W: int32(font.CharSize[0]), H: int32(font.CharSize[1])}
+	atlas.FillRect(&rect, 0)
+	copyPixels(glyph, nil, atlas, &sdl.Rect{X: rect.X, Y: rect.Y})
+
+	if font.GridHeight > 0 {
+		font.GridHeight = max(font.GridHeight, cell/font.GridWidth+1)
+	}
+	font.setAtlas(atlas)
+	font.CharSet += string(char)
+	font.CharWidths = append(slices.Clip(font.CharWidths), int(glyph.W)) // don't write into a slice shared with other fonts
+}
+
+// setAtlas swaps in a copy of the atlas AddGlyph has added to, dropping the
+// caches made from the old one without freeing them, as copies of the font
+// may share them
+func (font *Font) setAtlas(atlas *sdl.Surface) {
+	font.Atlas = atlas
+	font.numberCache = nil
+	font.smoothAtlases = nil
+}
+
+// addRect appends a glyph to a font with a rect table, onto a copy of the
+// atlas grown downwards to make room for it
+func (font *Font) addRect(char rune, glyph *sdl.Surface) {
+	y := font.Atlas.H + int32(font.cellPad())
+	grown := newSurface(max(int(font.Atlas.W), int(glyph.W)), int(y+glyph.H))
+	copyPixels(font.Atlas, nil, grown, &sdl.Rect{})
+	copyPixels(glyph, nil, grown, &sdl.Rect{Y: y})
+
+	font.setAtlas(grown)
+	font.CharSet += string(char)
+	font.Rects = append(slices.Clip(font.Rects), GlyphSource{
+		Rect:    sdl.Rect{Y: y, W: glyph.W, H: glyph.H},
+		Advance: int(glyph.W) + font.LetterPad,
+	})
+}
+
+// Subset returns a copy of the font with only the glyphs corpus needs, its
+// aliases, ligatures and shortcodes included, repacked into a new atlas, or
+// an error if it has none of them. Text from the corpus renders the same.
+func (font *Font) Subset(corpus string) (*Font, error) {
+	text := font.substitute(corpus)
+	runes := []rune(text)
+	needed := slices.Concat(runes, font.ligate(runes))
+	for _, seq := range slices.Sorted(maps.Keys(font.Ligatures)) {
+		if strings.Contains(text, seq) {
+			needed = append(needed, font.Ligatures[seq]) // even where a longer ligature won
+		}
+	}
+	if slices.Contains(runes, '\t') {
+		needed = append(needed, ' ')
+	}
+
+	var charSet []rune
+	var indices []int
+	for _, char := range needed {
+		if char == ligatureTail || char == '\n' {
+			continue
+		}
+		char, visible := font.displayRune(char)
+		if !visible || slices.Contains(charSet, char) {
+			continue
+		}
+		index := font.glyphIndex(char)
+		if index < 0 {
+			continue
+		}
+		charSet = append(charSet, char)
+		indices = append(indices, index)
+	}
+	if len(charSet) == 0 {
+		return nil, fmt.Errorf("Subset: the font has none of the glyphs of %q", corpus)
+	}
+
+	subset := *font
+	subset.CharSet = string(charSet)
+	subset.Bearings = nil
+	subset.Advances = nil
+	subset.AdvanceOverride = nil
+	subset.Combining = nil
+	subset.Aliases = nil
+	subset.Ligatures = nil
+	subset.Shortcodes = nil
+	subset.numberCache = nil
+	subset.smoothAtlases = nil
+	subset.indices = nil
+	subset.dpiBase, subset.dpiScale = nil, 0 // a subset of a scaled font keeps its scale for good
+	if font.Rects != nil {
+		subset.packRects(font, indices)
+	} else {
+		subset.packGrid(font, indices)
+	}
+
+	for i, char := range charSet {
+		if bearing := font.bearing(char); bearing != 0 {
+			if subset.Bearings == nil {
+				subset.Bearings = make([]int, len(indices))
+			}
+			subset.Bearings[i] = bearing
+		}
+		if font.Advances != nil && font.Rects == nil {
+			if subset.Advances == nil {
+				subset.Advances = make([]int, len(indices))
+			}
+			subset.Advances[i] = font.gridAdvance(indices[i], char)
+		}
+		if adv, ok := font.AdvanceOverride[char]; ok {
+			if subset.AdvanceOverride == nil {
+				subset.AdvanceOverride = make(map[rune]int)
+			}
+			subset.AdvanceOverride[char] = adv
+		}
+		if offset, ok := font.Combining[char]; ok {
+			if subset.Combining == nil {
+				subset.Combining = make(map[rune][2]int)
+			}
+			subset.Combining[char] = offset
+		}
+	}
+
+	// only what still has its glyph
+	for char, target := range font.Aliases {
+		if slices.Contains(charSet, target) {
+			if subset.Aliases == nil {
+				subset.Aliases = make(map[rune]rune)
+			}
+			subset.Aliases[char] = target
+		}
+	}
+	for seq, target := range font.Ligatures {
+		if slices.Contains(charSet, target) {
+			if subset.Ligatures == nil {
+				subset.Ligatures = make(map[string]rune)
+			}
+			subset.Ligatures[seq] = target
+		}
+	}
+	for name, char := range font.Shortcodes {
+		if slices.Contains(charSet, font.alias(char)) {
+			if subset.Shortcodes == nil {
+				subset.Shortcodes = make(map[string]rune)
+			}
+			subset.Shortcodes[name] = char
+		}
+	}
+
+	return &subset, nil
+}
+
+// packGrid gives a subset of font made of the glyphs at indices a new atlas,
+// copying their whole cells into a roughly square grid
+func (subset *Font) packGrid(font *Font, indices []int) {
+	cells, minWidth := len(indices), 1
+	for _, char := range subset.CharSet {
+		if font.DoubleWidth[char] {
+			cells, minWidth = cells+1, 2
+		}
+	}
+	subset.GridWidth = max(int(math.Ceil(math.Sqrt(float64(cells)))), minWidth)
+	subset.GridHeight = 0
+	subset.CharWidths = make([]int, len(indices))
+
+	rows := max((subset.cell(len(indices))+subset.GridWidth-1)/subset.GridWidth, 1)
+	subset.Atlas = newSurface(
+		subset.GridWidth*(font.CharSize[0]+font.cellPad())-font.cellPad(),
+		rows*(font.CharSize[1]+font.cellPad())-font.cellPad(),
+	)
+
+	chars := []rune(subset.CharSet)
+	for i, index := range indices {
+		fromX, fromY := font.cellOrigin(font.cell(index))
+		toX, toY := subset.cellOrigin(subset.cell(i))
+		width := font.CharSize[0]
+		if font.DoubleWidth[chars[i]] {
+			width = 2*font.CharSize[0] + font.cellPad()
+		}
+		copyPixels(
+			font.Atlas,
+			&sdl.Rect{X: int32(fromX), Y: int32(fromY), W: int32(width), H: int32(font.CharSize[1])},
+			subset.Atlas,
+			&sdl.Rect{X: int32(toX), Y: int32(toY)},
+		)
+		subset.CharWidths[i], _ = font.charWidth(index)
+	}
+}
+
+// packRects gives a subset of a font with a rect table made of the glyphs at
+// indices a new atlas and rect table, packing the glyphs into rows of a
+// roughly square atlas
+func (subset *Font) packRects(font *Font, indices []int) {
+	subset.Rects = make([]GlyphSource, len(indices))
+	subset.CharWidths = nil
+
+	area, widest := 0, 1
+	for _, index := range indices {
+		rect := font.Rects[index].Rect
+		area += int(rect.W+int32(font.cellPad())) * int(rect.H+int32(font.cellPad()))
+		widest = max(widest, int(rect.W))
+	}
+	rowWidth := max(int(math.Ceil(math.Sqrt(float64(area)))), widest)
+
+	// place each glyph left to right, starting a new row when one doesn't fit
+	x, y, rowHeight, width := 0, 0, 0, 1
+	for i, index := range indices {
+		rect := font.Rects[index].Rect
+		if x > 0 && x+int(rect.W) > rowWidth {
+			x, y, rowHeight = 0, y+rowHeight+font.cellPad(), 0
+		}
+		subset.Rects[i] = GlyphSource{Rect: sdl.Rect{X: int32(x), Y: int32(y), W: rect.W, H: rect.H}, Advance: font.Rects[index].Advance, Top: font.Rects[index].Top}
+		width = max(width, x+int(rect.W))
+		rowHeight = max(rowHeight, int(rect.H))
+		x += int(rect.W) + font.cellPad()
+	}
+	subset.Atlas = newSurface(width, max(y+rowHeight, 1))
+
+	for i, index := range indices {
+		from, to := font.Rects[index].Rect, subset.Rects[i].Rect
+		copyPixels(font.Atlas, &from, subset.Atlas, &to)
+	}
+}
+
+// BuildAtlas creates a Font from individual glyph images, packing them into
+// a new atlas of cellSize cells in a roughly square grid, ordered by code
+// point. Each glyph is as wide as its ink reaches from the left edge of its
+// image, or as wide as its image if it has no ink at all, like a space.
+// *sdl.Surface is an image.Image, so surfaces work as glyphs too. The atlas
+// can be saved with img.SavePNG for loading with NewFont later.
+func BuildAtlas(glyphs map[rune]image.Image, cellSize [2]int) (Font, error) {
+	chars := slices.Sorted(maps.Keys(glyphs))
+	if len(chars) == 0 {
+		return Font{}, fmt.Errorf("BuildAtlas: no glyphs")
+	}
+	for _, char := range chars {
+		if size := glyphs[char].Bounds().Size(); size.X > cellSize[0] || size.Y > cellSize[1] {
+			return Font{}, fmt.Errorf("BuildAtlas: %q is %dx%d, bigger than the %dx%d cell", char, size.X, size.Y, cellSize[0], cellSize[1])
+		}
+	}
+
+	gridWidth := int(math.Ceil(math.Sqrt(float64(len(chars)))))
+	font := fontFromAtlas(nil, gridWidth, string(chars), make([]int, len(chars)))
+	font.CharSize = cellSize
+	rows := (len(chars) + gridWidth - 1) / gridWidth
+	font.Atlas = newSurface(
+		gridWidth*(cellSize[0]+font.cellPad())-font.cellPad(),
+		rows*(cellSize[1]+font.cellPad())-font.cellPad(),
+	)
+
+	pixels := font.Atlas.Pixels()
+	for i, char := range chars {
+		glyph := glyphs[char]
+		bounds := glyph.Bounds()
+		originX, originY := font.cellOrigin(i)
+		inkWidth := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := color.NRGBAModel.Convert(glyph.At(x, y)).(color.NRGBA)
+				if c.A == 0 {
+					continue
+				}
+				inkWidth = max(inkWidth, x-bounds.Min.X+1)
+				pixel := sdl.MapRGBA(font.Atlas.Format, c.R, c.G, c.B, c.A)
+				setPixel(font.Atlas, pixels, originX+x-bounds.Min.X, originY+y-bounds.Min.Y, pixel)
+			}
+		}
+		if inkWidth == 0 {
+			inkWidth = bounds.Dx()
+		}
+		font.CharWidths[i] = inkWidth
+	}
+	return font, nil
+}
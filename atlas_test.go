@@ -0,0 +1,197 @@
+package font
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// solidGlyph returns a w by h glyph of opaque white
+func solidGlyph(w, h int) *sdl.Surface {
+	glyph := newSurface(w, h)
+	glyph.FillRect(nil, sdl.MapRGBA(glyph.Format, 255, 255, 255, 255))
+	return glyph
+}
+
+func TestAddGlyphLeavesCopiesAlone(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		rects bool
+	}{
+		{"grid", false},
+		{"rect table", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			font := MakeDefaultFont()
+			if test.rects {
+				font.Rects = font.RectTable()
+			}
+			atlas := rgba(font.Atlas)
+			before := font.RenderString("Hi", 1, 1, 1)
+
+			extended := font
+			extended.AddGlyph('é', solidGlyph(3, 4))
+			if extended.Atlas == font.Atlas {
+				t.Fatal("AddGlyph drew into the atlas the copy shares")
+			}
+			if after := rgba(font.Atlas); len(after) != len(atlas) {
+				t.Fatal("AddGlyph resized the copy's atlas")
+			} else {
+				for i := range atlas {
+					if after[i] != atlas[i] {
+						t.Fatalf("AddGlyph changed pixel %d of the copy's atlas", i)
+					}
+				}
+			}
+			assertSamePixels(t, "copy", font.RenderString("Hi", 1, 1, 1), before)
+			assertSamePixels(t, "extended", extended.RenderString("Hi", 1, 1, 1), before)
+
+			if first, last, ok := inkColumns(extended.RenderString("é", 1, 1, 1)); !ok || first != 0 || last != 2 {
+				t.Errorf("the added glyph has ink in columns %d to %d, want 0 to 2", first, last)
+			}
+			if font.glyphIndex('é') >= 0 {
+				t.Error("the copy has the added glyph")
+			}
+		})
+	}
+}
+
+func TestPackedAtlasRepacking(t *testing.T) {
+	font := packedFont()
+	subset, err := font.Subset("531")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subset.Atlas.W != 8 || subset.Atlas.H != 10 {
+		t.Errorf("subset atlas is %dx%d, want 8x10 with no gutters", subset.Atlas.W, subset.Atlas.H)
+	}
+	for i, char := range "531" {
+		want := sdl.Rect{X: int32(i % 2 * 4), Y: int32(i / 2 * 5), W: 4, H: 5}
+		if rect, ok := subset.GlyphRect(char); !ok || rect != want {
+			t.Errorf("subset GlyphRect(%q) = %v, %v, want %v", char, rect, ok, want)
+		}
+	}
+	assertSamePixels(t, "subset", subset.RenderString("135", 1, 1, 1), font.RenderString("135", 1, 1, 1))
+
+	font.AddGlyph('6', solidGlyph(4, 5))
+	if rect, ok := font.GlyphRect('6'); !ok || rect != (sdl.Rect{X: 0, Y: 10, W: 4, H: 5}) {
+		t.Errorf("added GlyphRect('6') = %v, %v, want the cell right below the last row", rect, ok)
+	}
+	if font.Atlas.W != 12 || font.Atlas.H != 15 {
+		t.Errorf("grown atlas is %dx%d, want 12x15 with no gutters", font.Atlas.W, font.Atlas.H)
+	}
+}
+
+func TestSubsetRendersLikeFont(t *testing.T) {
+	corpus := "Hello, World! -> :smile: café\tdone\n{1 + 2}"
+	for _, test := range []struct {
+		name  string
+		rects bool
+	}{
+		{"grid", false},
+		{"rect table", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			font := MakeDefaultFont()
+			if test.rects {
+				font.Rects = font.RectTable()
+			}
+			font.Aliases = map[rune]rune{'é': 'e'}
+			font.Ligatures = map[string]rune{"->": '☺'}
+			font.Shortcodes = map[string]rune{"smile": '☺'}
+
+			subset, err := font.Subset(corpus)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if subset.Atlas.W*subset.Atlas.H >= font.Atlas.W*font.Atlas.H {
+				t.Errorf("subset atlas is %dx%d, no smaller than the font's %dx%d", subset.Atlas.W, subset.Atlas.H, font.Atlas.W, font.Atlas.H)
+			}
+			if strings.ContainsRune(subset.CharSet, 'q') {
+				t.Errorf("subset charset %q has q, which the corpus doesn't", subset.CharSet)
+			}
+			assertSamePixels(t, "corpus", subset.RenderString(corpus, 1, 1, 1), font.RenderString(corpus, 1, 1, 1))
+			w, h := font.Measure(corpus)
+			if sw, sh := subset.Measure(corpus); sw != w || sh != h {
+				t.Errorf("subset Measure = %dx%d, want %dx%d", sw, sh, w, h)
+			}
+		})
+	}
+}
+
+func TestSubsetErrors(t *testing.T) {
+	font := MakeDefaultFont()
+	for _, corpus := range []string{"", "\n\n", "€£"} {
+		if _, err := font.Subset(corpus); err == nil {
+			t.Errorf("Subset(%q) succeeded with none of the font's glyphs", corpus)
+		}
+	}
+}
+
+func TestBuildAtlasRoundTrip(t *testing.T) {
+	// glyphs of different ink widths, one drawn from an offset image, and a
+	// blank space that keeps its whole width
+	glyph := func(rows ...string) image.Image {
+		out := image.NewNRGBA(image.Rect(3, 2, 3+len(rows[0]), 2+len(rows)))
+		for y, row := range rows {
+			for x, c := range row {
+				if c == '#' {
+					out.SetNRGBA(3+x, 2+y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+				}
+			}
+		}
+		return out
+	}
+	glyphs := map[rune]image.Image{
+		'i': glyph("#...", "....", "#...", "#..."),
+		'o': glyph(".##.", "#..#", "#..#", ".##."),
+		'v': glyph("#.#.", "#.#.", "#.#.", ".#.."),
+		' ': glyph("...", "...", "...", "..."),
+	}
+	font, err := BuildAtlas(glyphs, [2]int{4, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if font.CharSet != " iov" {
+		t.Errorf("charset %q, want the glyphs in code point order", font.CharSet)
+	}
+	for char, want := range map[rune]int{' ': 3, 'i': 1, 'o': 4, 'v': 3} {
+		if w, _ := font.Measure(string(char)); w != want+font.LetterPad {
+			t.Errorf("Measure(%q) = %d, want %d", char, w, want+font.LetterPad)
+		}
+	}
+	for char, source := range glyphs {
+		surface := font.RenderString(string(char), 1, 1, 1)
+		bounds := source.Bounds()
+		pixels := rgba(surface)
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < int(surface.W); x++ {
+				c := color.NRGBAModel.Convert(source.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+				if p := pixels[y*int(surface.W)+x]; p != [4]uint8{c.R, c.G, c.B, c.A} {
+					t.Errorf("%q: pixel %d,%d is %v, want %v", char, x, y, p, c)
+				}
+			}
+		}
+	}
+
+	// the atlas saved and loaded with NewFont draws the same
+	path := filepath.Join(t.TempDir(), "atlas.png")
+	if err := img.SavePNG(font.Atlas, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewFont(path, font.GridWidth, font.CharSet, font.CharWidths)
+	loaded.CharSize = font.CharSize
+	assertSamePixels(t, "loaded", loaded.RenderString("ivo vo", 1, 1, 1), font.RenderString("ivo vo", 1, 1, 1))
+
+	if _, err := BuildAtlas(nil, [2]int{4, 4}); err == nil {
+		t.Error("BuildAtlas with no glyphs succeeded")
+	}
+	if _, err := BuildAtlas(glyphs, [2]int{3, 4}); err == nil {
+		t.Error("BuildAtlas with glyphs bigger than the cell succeeded")
+	}
+}
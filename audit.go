@@ -0,0 +1,76 @@
+package font
+
+import (
+	"fmt"
+	"slices"
+	"unicode"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// AuditKind is a kind of problem Audit finds with a glyph
+type AuditKind int
+
+const (
+	AuditBlank        AuditKind = iota // the glyph's cell has no ink, so it draws as a gap
+	AuditInkPastWidth                  // ink reaches past the glyph's CharWidths entry, which is probably stale
+)
+
+// pixels fainter than this don't count as ink, so near invisible guide marks
+// that some atlases keep in unused parts of a cell aren't reported
+const auditInkAlpha = 16
+
+// AuditIssue is a problem Audit found with one character of the charset
+type AuditIssue struct {
+	Char   rune
+	Kind   AuditKind
+	Detail string
+}
+
+func (issue AuditIssue) String() string {
+	return fmt.Sprintf("%q: %s", issue.Char, issue.Detail)
+}
+
+// Audit scans the atlas for glyphs that look wrong without being errors:
+// characters whose cells are completely transparent, and ink that extends
+// past a character's CharWidths entry. Whitespace, the runes in allowBlank
+// and glyphs with a zero width are expected to be blank. Characters that
+// Validate rejects are skipped.
+func (font *Font) Audit(allowBlank ...rune) []AuditIssue {
+	atlas := font.Atlas
+	if atlas.MustLock() {
+		atlas.Lock()
+		defer atlas.Unlock()
+	}
+	pixels := atlas.Pixels()
+
+	var issues []AuditIssue
+	for _, char := range font.CharSet {
+		rect, err := font.glyphRect(char)
+		if err != nil {
+			continue
+		}
+
+		// scan the whole cell, since ink past the glyph's width is a problem too
+		scan := rect
+		if font.Rects == nil {
+			scan.W = int32(min(font.CharSize[0], int(atlas.W-rect.X)))
+		}
+		inkWidth := 0
+		for y := scan.Y; y < scan.Y+scan.H; y++ {
+			for x := scan.X; x < scan.X+scan.W; x++ {
+				if _, _, _, a := sdl.GetRGBA(getPixel(atlas, pixels, int(x), int(y)), atlas.Format); a >= auditInkAlpha {
+					inkWidth = max(inkWidth, int(x-scan.X)+1)
+				}
+			}
+		}
+
+		switch {
+		case inkWidth == 0 && rect.W > 0 && !unicode.IsSpace(char) && !slices.Contains(allowBlank, char):
+			issues = append(issues, AuditIssue{Char: char, Kind: AuditBlank, Detail: fmt.Sprintf("cell at %d,%d has no ink", rect.X, rect.Y)})
+		case inkWidth > int(rect.W):
+			issues = append(issues, AuditIssue{Char: char, Kind: AuditInkPastWidth, Detail: fmt.Sprintf("ink is %d wide but the glyph is %d wide", inkWidth, rect.W)})
+		}
+	}
+	return issues
+}
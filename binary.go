@@ -0,0 +1,105 @@
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"os"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// binaryVersion is the version of the binary font format SaveBinary writes
+const binaryVersion = 1
+
+// binaryMagic starts every binary font, before its version byte
+const binaryMagic = "TFNT"
+
+// SaveBinary writes the font to a single file holding its metrics and its
+// atlas's raw pixels, which LoadBinary reads back faster than LoadBundle
+// reads a bundle, with no JSON to parse or PNG to decode. The file is the
+// magic "TFNT", a version byte, the length of the metrics as a big endian
+// uint32, the metrics gob encoded and then the atlas's pixels, row by row,
+// as R, G, B and A bytes.
+func (font *Font) SaveBinary(path string) error {
+	var def bytes.Buffer
+	if err := gob.NewEncoder(&def).Encode(font.definition()); err != nil {
+		return fmt.Errorf("SaveBinary: %w", err)
+	}
+
+	atlas := font.Atlas
+	var buf bytes.Buffer
+	buf.Grow(len(binaryMagic) + 5 + def.Len() + int(atlas.W*atlas.H)*4)
+	buf.WriteString(binaryMagic)
+	buf.WriteByte(binaryVersion)
+	binary.Write(&buf, binary.BigEndian, uint32(def.Len()))
+	buf.Write(def.Bytes())
+
+	if atlas.MustLock() {
+		atlas.Lock()
+		defer atlas.Unlock()
+	}
+	pixels := atlas.Pixels()
+	for y := 0; y < int(atlas.H); y++ {
+		for x := 0; x < int(atlas.W); x++ {
+			r, g, b, a := sdl.GetRGBA(getPixel(atlas, pixels, x, y), atlas.Format)
+			buf.Write([]byte{r, g, b, a})
+		}
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// LoadBinary reads a font written by SaveBinary
+func LoadBinary(path string) (Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Font{}, err
+	}
+	font, err := unmarshalBinary(data)
+	if err != nil {
+		return Font{}, fmt.Errorf("binary font %s: %w", path, err)
+	}
+	return *font, nil
+}
+
+// unmarshalBinary reads a font from the contents of a binary font file,
+// checking that its definition matches its atlas
+func unmarshalBinary(data []byte) (*Font, error) {
+	header := len(binaryMagic) + 5
+	if len(data) < header || string(data[:len(binaryMagic)]) != binaryMagic {
+		return nil, fmt.Errorf("not a binary font")
+	}
+	if version := data[len(binaryMagic)]; version != binaryVersion {
+		return nil, fmt.Errorf("format version %d, only version %d is supported", version, binaryVersion)
+	}
+	defLen := int(binary.BigEndian.Uint32(data[len(binaryMagic)+1:]))
+	if defLen > len(data)-header {
+		return nil, fmt.Errorf("truncated definition")
+	}
+	var def bundleFile
+	if err := gob.NewDecoder(bytes.NewReader(data[header : header+defLen])).Decode(&def); err != nil {
+		return nil, fmt.Errorf("definition: %w", err)
+	}
+
+	w, h := def.AtlasSize[0], def.AtlasSize[1]
+	pixelData := data[header+defLen:]
+	if w <= 0 || h <= 0 || w > len(pixelData) || h > len(pixelData) || len(pixelData) != w*h*4 {
+		return nil, fmt.Errorf("%d bytes of pixels but the definition is for a %dx%d atlas", len(pixelData), w, h)
+	}
+	atlas := newSurface(w, h)
+	pixels := atlas.Pixels()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := pixelData[(y*w+x)*4:]
+			setPixel(atlas, pixels, x, y, sdl.MapRGBA(atlas.Format, p[0], p[1], p[2], p[3]))
+		}
+	}
+
+	font := def.font(atlas)
+	if err := font.Validate(); err != nil {
+		atlas.Free()
+		return nil, err
+	}
+	return font, nil
+}
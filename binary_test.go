@@ -0,0 +1,31 @@
+package font
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		font Font
+	}{
+		{"default", MakeDefaultFont()},
+		{"short width table", shortWidthFont()},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "font.tfnt")
+			if err := test.font.SaveBinary(path); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := LoadBinary(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer loaded.Atlas.Free()
+			assertSamePixels(t, "atlas", loaded.Atlas, test.font.Atlas)
+			text := "Hello, World! xyz"
+			assertSamePixels(t, "text", loaded.RenderString(text, 1, 1, 1), test.font.RenderString(text, 1, 1, 1))
+		})
+	}
+}
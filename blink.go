@@ -0,0 +1,41 @@
+package font
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// RenderBlink draws the layout like Render, except that the glyphs of runes
+// in the blink ranges are only drawn when visible is set. Both phases are the
+// same size, with every other glyph in the same place, so a caller can swap
+// between them on a timer, like a blinking cursor or an "INSERT COIN" prompt.
+// Ranges are rune indices of the text passed in, end exclusive, and a hyphen
+// inserted where a blinking word was split blinks with it.
+func (layout *Layout) RenderBlink(blink []RuneRange, visible bool, r, g, b float64) *sdl.Surface {
+	surface := layout.font.outputSurface(layout.width, layout.height)
+	layout.fillBackgrounds(surface, 0, 0)
+	tint := sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+
+	prev := -1 // index of the glyph before, which a hyphen belongs to
+	layout.compositeGlyphs(surface, 0, 0, tint, func(glyph GlyphPlacement) bool {
+		index := glyph.Index
+		if index < 0 {
+			index = prev
+		}
+		prev = index
+		if visible {
+			return true
+		}
+		for _, span := range blink {
+			if index >= span.Start && index < span.End {
+				return false
+			}
+		}
+		return true
+	})
+	return surface
+}
+
+// RenderStringBlink draws text with RenderBlink in both phases, blinking
+// runes shown and hidden
+func (font *Font) RenderStringBlink(text string, blink []RuneRange, r, g, b float64) (on, off *sdl.Surface) {
+	layout := font.layout(text, LayoutOptions{})
+	return layout.RenderBlink(blink, true, r, g, b), layout.RenderBlink(blink, false, r, g, b)
+}
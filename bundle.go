@@ -0,0 +1,247 @@
+package font
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// bundleVersion is the version of the bundle format SaveBundle writes
+const bundleVersion = 1
+
+// names of the files inside a bundle
+const (
+	bundleDefinition = "font.json"
+	bundleAtlas      = "atlas.png"
+)
+
+// bundleFile is the definition stored in a bundle next to its atlas
+type bundleFile struct {
+	Version            int               `json:"version"`
+	AtlasSize          [2]int            `json:"atlasSize"` // checked against the bundled atlas
+	CharSet            string            `json:"charset"`
+	GridWidth          int               `json:"gridWidth"`
+	GridHeight         int               `json:"gridHeight,omitempty"`
+	CellSize           [2]int            `json:"cellSize"`
+	CellPad            int               `json:"cellPad"`
+	Packed             bool              `json:"packed,omitempty"`
+	Widths             []int             `json:"widths,omitempty"`
+	DefaultWidth       int               `json:"defaultWidth,omitempty"`
+	Bearings           []int             `json:"bearings,omitempty"`
+	Advances           []int             `json:"advances,omitempty"`
+	Rects              [][6]int          `json:"rects,omitempty"` // x, y, w, h, advance and top of each glyph
+	LetterPad          int               `json:"letterPad"`
+	WordSpacing        int               `json:"wordSpacing,omitempty"`
+	NewlinePad         int               `json:"newlinePad"`
+	TabSpaces          int               `json:"tabSpaces,omitempty"`
+	AdvanceOverride    map[rune]int      `json:"advanceOverride,omitempty"`
+	Combining          map[rune][2]int   `json:"combining,omitempty"`
+	Aliases            map[rune]rune     `json:"aliases,omitempty"`
+	Ligatures          map[string]rune   `json:"ligatures,omitempty"`
+	Shortcodes         map[string]rune   `json:"shortcodes,omitempty"`
+	DoubleWidth        map[rune]bool     `json:"doubleWidth,omitempty"`
+	HangingPunctuation map[rune]int      `json:"hangingPunctuation,omitempty"`
+	CollapseWhitespace bool              `json:"collapseWhitespace,omitempty"`
+	CollapseNewlines   bool              `json:"collapseNewlines,omitempty"`
+	ControlChars       ControlCharPolicy `json:"controlChars,omitempty"`
+	Overstrike         bool              `json:"overstrike,omitempty"`
+}
+
+// SaveBundle writes the font to a single file holding both its atlas and its
+// metrics, which LoadBundle reads back. The bundle is a zip archive.
+func (font *Font) SaveBundle(path string) error {
+	data, err := font.MarshalBundle()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// definition returns the font's metrics as they're stored in a bundle or a
+// binary font, with no version set
+func (font *Font) definition() bundleFile {
+	def := bundleFile{
+		AtlasSize:          [2]int{int(font.Atlas.W), int(font.Atlas.H)},
+		CharSet:            font.CharSet,
+		GridWidth:          font.GridWidth,
+		GridHeight:         font.GridHeight,
+		CellSize:           font.CharSize,
+		CellPad:            font.CellPad,
+		Packed:             font.Packed,
+		Widths:             font.CharWidths,
+		DefaultWidth:       font.DefaultCharWidth,
+		Bearings:           font.Bearings,
+		Advances:           font.Advances,
+		LetterPad:          font.LetterPad,
+		WordSpacing:        font.WordSpacing,
+		NewlinePad:         font.NewlinePad,
+		TabSpaces:          font.TabSpaces,
+		AdvanceOverride:    font.AdvanceOverride,
+		Combining:          font.Combining,
+		Aliases:            font.Aliases,
+		Ligatures:          font.Ligatures,
+		Shortcodes:         font.Shortcodes,
+		DoubleWidth:        font.DoubleWidth,
+		HangingPunctuation: font.HangingPunctuation,
+		CollapseWhitespace: font.CollapseWhitespace,
+		CollapseNewlines:   font.CollapseNewlines,
+		ControlChars:       font.ControlChars,
+		Overstrike:         font.Overstrike,
+	}
+	for _, g := range font.Rects {
+		def.Rects = append(def.Rects, [6]int{int(g.Rect.X), int(g.Rect.Y), int(g.Rect.W), int(g.Rect.H), g.Advance, g.Top})
+	}
+	return def
+}
+
+// MarshalBundle returns the font as a bundle like SaveBundle writes
+func (font *Font) MarshalBundle() ([]byte, error) {
+	def := font.definition()
+	def.Version = bundleVersion
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, err := archive.Create(bundleDefinition)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(def); err != nil {
+		return nil, err
+	}
+	w, err = archive.Create(bundleAtlas)
+	if err != nil {
+		return nil, err
+	}
+	if err := png.Encode(w, surfaceImage(font.Atlas)); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadBundle reads a font written by SaveBundle
+func LoadBundle(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	font, err := UnmarshalBundle(data)
+	if err != nil {
+		return nil, fmt.Errorf("bundle %s: %w", path, err)
+	}
+	return font, nil
+}
+
+// LoadBundleFS reads a font written by SaveBundle from fsys, such as an embed.FS
+func LoadBundleFS(fsys fs.FS, name string) (*Font, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	font, err := UnmarshalBundle(data)
+	if err != nil {
+		return nil, fmt.Errorf("bundle %s: %w", name, err)
+	}
+	return font, nil
+}
+
+// readBundled reads a whole file from a bundle
+func readBundled(archive *zip.Reader, name string) ([]byte, error) {
+	f, err := archive.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// UnmarshalBundle reads a font from the contents of a bundle, checking that
+// its definition matches its atlas
+func UnmarshalBundle(data []byte) (*Font, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	defData, err := readBundled(archive, bundleDefinition)
+	if err != nil {
+		return nil, err
+	}
+	var def bundleFile
+	if err := json.Unmarshal(defData, &def); err != nil {
+		return nil, fmt.Errorf("%s: %w", bundleDefinition, err)
+	}
+	if def.Version != bundleVersion {
+		return nil, fmt.Errorf("format version %d, only version %d is supported", def.Version, bundleVersion)
+	}
+
+	atlasData, err := readBundled(archive, bundleAtlas)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := png.Decode(bytes.NewReader(atlasData))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", bundleAtlas, err)
+	}
+	if size := decoded.Bounds().Size(); size.X != def.AtlasSize[0] || size.Y != def.AtlasSize[1] {
+		return nil, fmt.Errorf("atlas is %dx%d but the definition is for a %dx%d atlas", size.X, size.Y, def.AtlasSize[0], def.AtlasSize[1])
+	}
+
+	font := def.font(imageSurface(decoded))
+	if err := font.Validate(); err != nil {
+		font.Atlas.Free()
+		return nil, err
+	}
+	return font, nil
+}
+
+// font returns a font with the definition's metrics and atlas
+func (def *bundleFile) font(atlas *sdl.Surface) *Font {
+	font := &Font{
+		Atlas:              atlas,
+		GridWidth:          def.GridWidth,
+		GridHeight:         def.GridHeight,
+		CharSize:           def.CellSize,
+		CellPad:            def.CellPad,
+		Packed:             def.Packed,
+		CharSet:            def.CharSet,
+		CharWidths:         def.Widths,
+		DefaultCharWidth:   def.DefaultWidth,
+		Bearings:           def.Bearings,
+		Advances:           def.Advances,
+		NewlinePad:         def.NewlinePad,
+		TabSpaces:          def.TabSpaces,
+		LetterPad:          def.LetterPad,
+		WordSpacing:        def.WordSpacing,
+		AdvanceOverride:    def.AdvanceOverride,
+		Combining:          def.Combining,
+		Aliases:            def.Aliases,
+		Ligatures:          def.Ligatures,
+		Shortcodes:         def.Shortcodes,
+		DoubleWidth:        def.DoubleWidth,
+		HangingPunctuation: def.HangingPunctuation,
+		CollapseWhitespace: def.CollapseWhitespace,
+		CollapseNewlines:   def.CollapseNewlines,
+		ControlChars:       def.ControlChars,
+		Overstrike:         def.Overstrike,
+	}
+	for _, g := range def.Rects {
+		font.Rects = append(font.Rects, GlyphSource{
+			Rect:    sdl.Rect{X: int32(g[0]), Y: int32(g[1]), W: int32(g[2]), H: int32(g[3])},
+			Advance: g[4],
+			Top:     g[5],
+		})
+	}
+	return font
+}
@@ -0,0 +1,163 @@
+package font
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		rects bool
+	}{
+		{"grid", false},
+		{"rect table", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			font := MakeDefaultFont()
+			if test.rects {
+				font.Rects = font.RectTable()
+			}
+			font.Aliases = map[rune]rune{'é': 'e'}
+			font.Ligatures = map[string]rune{"->": '☺'}
+			font.AdvanceOverride = map[rune]int{'i': 3}
+			font.TabSpaces = 2
+
+			path := filepath.Join(t.TempDir(), "font.bundle")
+			if err := font.SaveBundle(path); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := LoadBundle(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer loaded.Atlas.Free()
+
+			assertSamePixels(t, "atlas", loaded.Atlas, font.Atlas)
+			text := "café -> fill\tit\nHello, World!"
+			assertSamePixels(t, "text", loaded.RenderString(text, 1, 1, 1), font.RenderString(text, 1, 1, 1))
+			w, h := font.Measure(text)
+			if lw, lh := loaded.Measure(text); lw != w || lh != h {
+				t.Errorf("loaded Measure = %dx%d, want %dx%d", lw, lh, w, h)
+			}
+		})
+	}
+}
+
+// shortWidthFont returns the default font with a width table that stops
+// part way through its charset, DefaultCharWidth covering the rest
+func shortWidthFont() Font {
+	font := MakeDefaultFont()
+	font.CharWidths = font.CharWidths[:10]
+	font.DefaultCharWidth = 4
+	return font
+}
+
+func TestBundleDefaultCharWidth(t *testing.T) {
+	font := shortWidthFont()
+	if err := font.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "font.bundle")
+	if err := font.SaveBundle(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBundle(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loaded.Atlas.Free()
+	if loaded.DefaultCharWidth != 4 || len(loaded.CharWidths) != 10 {
+		t.Errorf("loaded %d widths and a default of %d, want 10 and 4", len(loaded.CharWidths), loaded.DefaultCharWidth)
+	}
+	text := "Hello, World! xyz"
+	assertSamePixels(t, "text", loaded.RenderString(text, 1, 1, 1), font.RenderString(text, 1, 1, 1))
+
+	font.DefaultCharWidth = 0
+	if err := font.Validate(); err == nil {
+		t.Error("Validate passed a short width table with no DefaultCharWidth")
+	}
+	font.DefaultCharWidth = 4
+	font.CharWidths = make([]int, len([]rune(font.CharSet))+1)
+	if err := font.Validate(); err == nil {
+		t.Error("Validate passed a width table longer than the charset")
+	}
+}
+
+// bundleOf returns a zip archive of the given files
+func bundleOf(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCorruptedBundle(t *testing.T) {
+	font := MakeDefaultFont()
+	data, err := font.MarshalBundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := readBundled(archive, bundleDefinition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atlas, err := readBundled(archive, bundleAtlas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := func(old, new string) []byte {
+		if !bytes.Contains(def, []byte(old)) {
+			t.Fatalf("the definition has no %q", old)
+		}
+		return bytes.Replace(def, []byte(old), []byte(new), 1)
+	}
+
+	for _, test := range []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"not a zip", []byte("tiny font"), "zip"},
+		{"truncated", data[:len(data)/2], "zip"},
+		{"no definition", bundleOf(t, map[string][]byte{bundleAtlas: atlas}), bundleDefinition},
+		{"no atlas", bundleOf(t, map[string][]byte{bundleDefinition: def}), bundleAtlas},
+		{"bad definition", bundleOf(t, map[string][]byte{bundleDefinition: def[:len(def)/2], bundleAtlas: atlas}), bundleDefinition},
+		{"bad atlas", bundleOf(t, map[string][]byte{bundleDefinition: def, bundleAtlas: atlas[:len(atlas)/2]}), bundleAtlas},
+		{"newer version", bundleOf(t, map[string][]byte{bundleDefinition: edited(`"version": 1`, `"version": 2`), bundleAtlas: atlas}), "version 2"},
+		{"wrong atlas size", bundleOf(t, map[string][]byte{bundleDefinition: edited(`"gridWidth"`, `"atlasSize": [1, 1], "gridWidth"`), bundleAtlas: atlas}), "1x1 atlas"},
+		{"bad metrics", bundleOf(t, map[string][]byte{bundleDefinition: edited(`"gridWidth"`, `"gridWidth": 0, "ignored"`), bundleAtlas: atlas}), "grid width 0"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			loaded, err := UnmarshalBundle(test.data)
+			if err == nil {
+				loaded.Atlas.Free()
+				t.Fatal("UnmarshalBundle succeeded")
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("UnmarshalBundle error %q doesn't mention %q", err, test.want)
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "font.bundle")
+	if _, err := LoadBundle(path); err == nil {
+		t.Error("LoadBundle of a missing file succeeded")
+	}
+}
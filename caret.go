@@ -0,0 +1,130 @@
+package font
+
+import (
+	"sort"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// laidOutIndex maps a rune index in the text passed in to the index of the
+// first rune of Text at or after it, undoing SourceIndex
+func (layout *Layout) laidOutIndex(index int) int {
+	if layout.source == nil {
+		return min(max(index, 0), len(layout.wrap.Text))
+	}
+	return min(sort.SearchInts(layout.source, index), len(layout.wrap.Text))
+}
+
+// caretLine returns the line a caret before rune index of Text is on. A caret
+// where a line wraps is at the start of the next line, and one in the runes
+// a break consumed is at the end of their line.
+func (layout *Layout) caretLine(index int) int {
+	line, _ := layout.wrap.OriginalToWrapped(index)
+	return line
+}
+
+// caretX returns the x of a caret before rune index of Text on line
+func (layout *Layout) caretX(index, line int) int {
+	l := layout.wrap.Lines[line]
+	switch {
+	case index < l.End:
+		return int(layout.cells[index].X)
+	case l.End > l.Start:
+		last := layout.cells[l.End-1]
+		return int(last.X + last.W)
+	}
+	return layout.lineX[line]
+}
+
+// LineStart returns the index of the first rune of the line the caret before
+// index is on. Indices are runes of the text passed in, like GlyphPlacement's.
+func (layout *Layout) LineStart(index int) int {
+	line := layout.caretLine(layout.laidOutIndex(index))
+	return layout.SourceIndex(layout.wrap.Lines[line].Start)
+}
+
+// LineEnd returns the index just past the last rune drawn on the line the
+// caret before index is on, before any spaces or newline its break consumed
+func (layout *Layout) LineEnd(index int) int {
+	line := layout.caretLine(layout.laidOutIndex(index))
+	return layout.SourceIndex(layout.wrap.Lines[line].End)
+}
+
+// CaretX returns the x of a caret before index, relative to the text origin,
+// for starting the preferred x that IndexAbove and IndexBelow keep to
+func (layout *Layout) CaretX(index int) int {
+	i := layout.laidOutIndex(index)
+	return layout.caretX(i, layout.caretLine(i))
+}
+
+// IndexAbove returns where a caret before index moves to on the line above:
+// the caret position on it closest to x pixels from the text origin. Passing
+// the same x, from CaretX before the first move, through a series of moves
+// keeps the caret's column when it passes through shorter lines. From the
+// first line it moves to the start of the text.
+func (layout *Layout) IndexAbove(index, x int) int {
+	line := layout.caretLine(layout.laidOutIndex(index))
+	if line == 0 {
+		return 0
+	}
+	return layout.indexAt(line-1, x)
+}
+
+// IndexBelow is IndexAbove for the line below. From the last line it moves
+// to the end of the text.
+func (layout *Layout) IndexBelow(index, x int) int {
+	line := layout.caretLine(layout.laidOutIndex(index))
+	if line == len(layout.wrap.Lines)-1 {
+		return layout.SourceIndex(len(layout.wrap.Text))
+	}
+	return layout.indexAt(line+1, x)
+}
+
+// indexAt returns the caret position on line closest to x, never inside a
+// grapheme cluster
+func (layout *Layout) indexAt(line, x int) int {
+	l := layout.wrap.Lines[line]
+	best, bestDist := l.Start, -1
+	for i := l.Start; i <= l.End; i++ {
+		if i == l.End && i == layout.wrap.next(line) && line < len(layout.wrap.Lines)-1 {
+			break // that's the start of the next line
+		}
+		if layout.font.continuesCluster(layout.wrap.Text, layout.wrap.glyphs, i) {
+			continue
+		}
+		dist := layout.caretX(i, line) - x
+		if dist < 0 {
+			dist = -dist
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return layout.SourceIndex(best)
+}
+
+// RenderWithCaret draws the layout like Render with a caret before index: a
+// bar width pixels wide and as tall as a line, in the text color. The surface
+// is widened if the caret is past the end of the widest line, up to
+// opts.MaxSurfaceWidth. The caret is
+// left out when visible is false, for blinking it, without changing the size.
+func (layout *Layout) RenderWithCaret(index, width int, visible bool, r, g, b float64) *sdl.Surface {
+	i := layout.laidOutIndex(index)
+	line := layout.caretLine(i)
+	x := layout.caretX(i, line)
+	width = max(width, 1)
+
+	surface := layout.font.outputSurface(layout.capWidth(0, max(layout.width, x+width)), layout.height)
+	layout.CompositeInto(surface, 0, 0, r, g, b)
+	if visible {
+		bar := sdl.Rect{X: int32(x), Y: int32(layout.lineY(line)), W: int32(width), H: int32(layout.font.CharSize[1])}
+		surface.FillRect(&bar, sdl.MapRGBA(surface.Format, uint8(r*255), uint8(g*255), uint8(b*255), 255))
+	}
+	return surface
+}
+
+// RenderStringWithCaret draws text like RenderString with a 1px caret before
+// rune caretIndex, for a text input
+func (font *Font) RenderStringWithCaret(text string, caretIndex int, r, g, b float64) *sdl.Surface {
+	return font.layout(text, LayoutOptions{}).RenderWithCaret(caretIndex, 1, true, r, g, b)
+}
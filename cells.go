@@ -0,0 +1,30 @@
+package font
+
+// CellSize returns the size of a character cell for laying text out in rows
+// and columns: the widest advance of any single width glyph, and the line
+// height. In a monospace font every glyph advances exactly one cell.
+func (font *Font) CellSize() (w, h int) {
+	for _, char := range font.CharSet {
+		if font.DoubleWidth[char] || font.isCombining(char) {
+			continue
+		}
+		if adv, ok := font.advance(char); ok {
+			w = max(w, adv)
+		}
+	}
+	return w, font.LineHeight()
+}
+
+// SizeInCells returns how many CellSize cells text takes up: the columns its
+// widest line needs and its number of lines. Lines of a proportional font
+// are rounded up to whole cells.
+func (font *Font) SizeInCells(text string) (cols, rows int) {
+	cellW, _ := font.CellSize()
+	layout := font.layout(text, LayoutOptions{})
+	for _, line := range layout.wrap.Lines {
+		if cellW > 0 {
+			cols = max(cols, (line.Width+cellW-1)/cellW)
+		}
+	}
+	return cols, len(layout.wrap.Lines)
+}
@@ -0,0 +1,55 @@
+package font
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// defaultCheckboxMarkers are the markers RenderCheckbox chooses from when the
+// font's CheckboxMarkers is empty
+var defaultCheckboxMarkers = [][2]string{
+	{"☐", "☑"},
+	{"✗", "✓"},
+	{"[ ]", "[x]"},
+}
+
+// RenderCheckbox draws the checked or unchecked marker of the first pair of
+// CheckboxMarkers the font has every glyph of, so both states always come
+// from the same pair, falling back to the last pair if it has none of them
+func (font *Font) RenderCheckbox(checked bool, r, g, b float64) *sdl.Surface {
+	pair := checkboxMarkers(font.CheckboxMarkers, func(text string) bool { return len(font.MissingRunes(text)) == 0 })
+	if checked {
+		return font.RenderString(pair[1], r, g, b)
+	}
+	return font.RenderString(pair[0], r, g, b)
+}
+
+// RenderCheckbox draws a checkbox marker with renderer the way
+// Font.RenderCheckbox does. A renderer that isn't a *Font chooses from the
+// default markers, and only skips ones it can't draw if it has a MissingRunes
+// method like Font's; otherwise it draws the first pair.
+func RenderCheckbox(renderer TextRenderer, checked bool, r, g, b float64) (*sdl.Surface, error) {
+	if font, ok := renderer.(*Font); ok {
+		return font.RenderCheckbox(checked, r, g, b), nil
+	}
+	has := func(string) bool { return true }
+	if coverage, ok := renderer.(interface{ MissingRunes(text string) []rune }); ok {
+		has = func(text string) bool { return len(coverage.MissingRunes(text)) == 0 }
+	}
+	pair := checkboxMarkers(nil, has)
+	if checked {
+		return renderer.Render(pair[1], r, g, b)
+	}
+	return renderer.Render(pair[0], r, g, b)
+}
+
+// checkboxMarkers returns the first pair of choices, or of the default
+// markers when there are none, that has every glyph, or else the last pair
+func checkboxMarkers(choices [][2]string, has func(text string) bool) [2]string {
+	if len(choices) == 0 {
+		choices = defaultCheckboxMarkers
+	}
+	for _, markers := range choices {
+		if has(markers[0] + markers[1]) {
+			return markers
+		}
+	}
+	return choices[len(choices)-1]
+}
@@ -0,0 +1,23 @@
+package font
+
+import "testing"
+
+func TestRenderCheckbox(t *testing.T) {
+	font := MakeDefaultFont()
+	for _, test := range []struct {
+		name             string
+		markers          [][2]string
+		unchecked, check string
+	}{
+		{"default", nil, "[ ]", "[x]"},
+		{"first the font has", [][2]string{{"☐", "☑"}, {"-", "+"}, {"o", "x"}}, "-", "+"},
+		{"last if none", [][2]string{{"☐", "☑"}, {"✓", "x"}}, "✓", "x"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			font := font
+			font.CheckboxMarkers = test.markers
+			assertSamePixels(t, "unchecked", font.RenderCheckbox(false, 1, 1, 1), font.RenderString(test.unchecked, 1, 1, 1))
+			assertSamePixels(t, "checked", font.RenderCheckbox(true, 1, 1, 1), font.RenderString(test.check, 1, 1, 1))
+		})
+	}
+}
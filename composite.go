@@ -0,0 +1,158 @@
+package font
+
+import (
+	"encoding/binary"
+	"image/color"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// getPixel reads the raw pixel at x, y
+func getPixel(surface *sdl.Surface, pixels []byte, x, y int) uint32 {
+	bpp := int(surface.Format.BytesPerPixel)
+	i := y*int(surface.Pitch) + x*bpp
+	switch bpp {
+	case 1:
+		return uint32(pixels[i])
+	case 2:
+		return uint32(binary.NativeEndian.Uint16(pixels[i:]))
+	case 3:
+		return uint32(pixels[i]) | uint32(pixels[i+1])<<8 | uint32(pixels[i+2])<<16
+	default:
+		return binary.NativeEndian.Uint32(pixels[i:])
+	}
+}
+
+// setPixel writes the raw pixel at x, y
+func setPixel(surface *sdl.Surface, pixels []byte, x, y int, pixel uint32) {
+	bpp := int(surface.Format.BytesPerPixel)
+	i := y*int(surface.Pitch) + x*bpp
+	switch bpp {
+	case 1:
+		pixels[i] = uint8(pixel)
+	case 2:
+		binary.NativeEndian.PutUint16(pixels[i:], uint16(pixel))
+	case 3:
+		pixels[i], pixels[i+1], pixels[i+2] = uint8(pixel), uint8(pixel>>8), uint8(pixel>>16)
+	default:
+		binary.NativeEndian.PutUint32(pixels[i:], pixel)
+	}
+}
+
+// blend mixes src over dst the way a BLENDMODE_BLEND blit does
+func blend(src, dst, alpha uint8) uint8 {
+	return uint8((uint32(src)*uint32(alpha) + uint32(dst)*(255-uint32(alpha)) + 127) / 255)
+}
+
+// compositeGlyph draws the atlas pixels in src at dst, tinted the way a color
+// and alpha modded blit would be, without touching the atlas. Pixels outside
+// the destination's clip rect are left alone.
+func compositeGlyph(atlas *sdl.Surface, src sdl.Rect, dst *sdl.Surface, x, y int, tint sdl.Color) {
+	clip := dst.ClipRect
+	from, to := atlas.Pixels(), dst.Pixels()
+	for row := 0; row < int(src.H); row++ {
+		dy := y + row
+		if dy < int(clip.Y) || dy >= int(clip.Y+clip.H) {
+			continue
+		}
+		for col := 0; col < int(src.W); col++ {
+			dx := x + col
+			if dx < int(clip.X) || dx >= int(clip.X+clip.W) {
+				continue
+			}
+			sr, sg, sb, sa := sdl.GetRGBA(getPixel(atlas, from, int(src.X)+col, int(src.Y)+row), atlas.Format)
+			sa = uint8(uint32(sa) * uint32(tint.A) / 255)
+			if sa == 0 {
+				continue
+			}
+			sr = uint8(uint32(sr) * uint32(tint.R) / 255)
+			sg = uint8(uint32(sg) * uint32(tint.G) / 255)
+			sb = uint8(uint32(sb) * uint32(tint.B) / 255)
+
+			dr, dg, db, da := sdl.GetRGBA(getPixel(dst, to, dx, dy), dst.Format)
+			da = sa + uint8(uint32(da)*(255-uint32(sa))/255)
+			setPixel(dst, to, dx, dy, sdl.MapRGBA(dst.Format, blend(sr, dr, sa), blend(sg, dg, sa), blend(sb, db, sa), da))
+		}
+	}
+}
+
+// enlarge copies the pixels of src in rect onto dst, n times as big with the
+// top left corner at x, y, tinted like a color modded blit but replacing what
+// was there, alpha included. Unlike BlitScaled, whose fixed point steps drop a
+// row or column at scales like 3, every pixel becomes exactly n by n.
+func enlarge(src *sdl.Surface, rect sdl.Rect, dst *sdl.Surface, x, y, n int, tint sdl.Color) {
+	if src.MustLock() {
+		src.Lock()
+		defer src.Unlock()
+	}
+	if dst.MustLock() {
+		dst.Lock()
+		defer dst.Unlock()
+	}
+	from, to := src.Pixels(), dst.Pixels()
+	for row := 0; row < int(rect.H)*n; row++ {
+		dy := y + row
+		if dy < 0 || dy >= int(dst.H) {
+			continue
+		}
+		for col := 0; col < int(rect.W)*n; col++ {
+			dx := x + col
+			if dx < 0 || dx >= int(dst.W) {
+				continue
+			}
+			r, g, b, a := sdl.GetRGBA(getPixel(src, from, int(rect.X)+col/n, int(rect.Y)+row/n), src.Format)
+			r = uint8(uint32(r) * uint32(tint.R) / 255)
+			g = uint8(uint32(g) * uint32(tint.G) / 255)
+			b = uint8(uint32(b) * uint32(tint.B) / 255)
+			setPixel(dst, to, dx, dy, sdl.MapRGBA(dst.Format, r, g, b, a))
+		}
+	}
+}
+
+// CompositeInto draws the layout onto dst with the text origin at x, y by
+// reading the atlas pixels and writing tinted ones itself, so unlike
+// RenderInto it never changes the atlas's color mod. Any number of layouts of
+// the same font can composite at once, in any colors, as long as each
+// goroutine has its own dst. Surfaces that must be locked, such as RLE
+// accelerated ones, are locked while they're read or written.
+func (layout *Layout) CompositeInto(dst *sdl.Surface, x, y int, r, g, b float64) {
+	layout.composite(dst, x, y, sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255})
+}
+
+// composite does the work of CompositeInto with a tint that can be translucent
+func (layout *Layout) composite(dst *sdl.Surface, x, y int, tint sdl.Color) {
+	layout.fillBackgrounds(dst, x, y)
+	layout.compositeGlyphs(dst, x, y, tint, func(GlyphPlacement) bool { return true })
+}
+
+// compositeGlyphs composites the glyphs keep accepts onto dst
+func (layout *Layout) compositeGlyphs(dst *sdl.Surface, x, y int, tint sdl.Color, keep func(glyph GlyphPlacement) bool) {
+	defer layout.clipToCap(dst, x)()
+	atlas := layout.font.Atlas
+	if atlas.MustLock() {
+		atlas.Lock()
+		defer atlas.Unlock()
+	}
+	if dst.MustLock() {
+		dst.Lock()
+		defer dst.Unlock()
+	}
+
+	layout.Walk(func(glyph GlyphPlacement) bool {
+		if keep(glyph) {
+			compositeGlyph(atlas, glyph.Src, dst, x+int(glyph.Dst.X), y+int(glyph.Dst.Y), tint)
+		}
+		return true
+	})
+}
+
+// RenderStringColor draws text like RenderString in a standard library color,
+// so colors can be shared with code that doesn't use SDL. A translucent color
+// draws translucent text.
+func (font *Font) RenderStringColor(text string, c color.Color) *sdl.Surface {
+	tint := color.NRGBAModel.Convert(c).(color.NRGBA)
+	layout := font.layout(text, LayoutOptions{})
+	surface := layout.font.outputSurface(layout.width, layout.height)
+	layout.composite(surface, 0, 0, sdl.Color{R: tint.R, G: tint.G, B: tint.B, A: tint.A})
+	return surface
+}
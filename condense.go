@@ -0,0 +1,50 @@
+package font
+
+import (
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// ellipsis returns what cut off text ends with
+func (font *Font) ellipsis() string {
+	if font.glyphIndex('…') >= 0 {
+		return "…"
+	}
+	return "..."
+}
+
+// truncate cuts each line of text that's wider than maxWidth short, ending
+// it with an ellipsis
+func (font *Font) truncate(text string, maxWidth int) string {
+	lines := strings.Split(text, "\n")
+	ellipsis := font.ellipsis()
+	for i, line := range lines {
+		if font.layout(line, LayoutOptions{}).width <= maxWidth {
+			continue
+		}
+		runes := []rune(line)
+		for len(runes) > 0 && font.layout(string(runes)+ellipsis, LayoutOptions{}).width > maxWidth {
+			runes = runes[:len(runes)-1]
+		}
+		lines[i] = string(runes) + ellipsis
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderStringCondensed draws text like RenderString, but if it's wider than
+// maxWidth the letter spacing is tightened evenly, as far as minLetterPad, to
+// make it fit. Lines that don't fit even then are cut short with an ellipsis.
+func (font *Font) RenderStringCondensed(text string, maxWidth, minLetterPad int, r, g, b float64) *sdl.Surface {
+	condensed := *font
+	condensed.numberCache = nil // the copy's metrics differ
+	layout := condensed.layout(text, LayoutOptions{})
+	for layout.width > maxWidth && condensed.LetterPad > minLetterPad {
+		condensed.LetterPad--
+		layout = condensed.layout(text, LayoutOptions{})
+	}
+	if layout.width > maxWidth {
+		layout = condensed.layout(condensed.truncate(text, maxWidth), LayoutOptions{})
+	}
+	return layout.Render(r, g, b)
+}
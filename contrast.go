@@ -0,0 +1,136 @@
+package font
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// defaultContrastThreshold is the background luminance at which black and
+// white text have the same contrast ratio
+const defaultContrastThreshold = 0.179
+
+// contrastThreshold returns threshold, or defaultContrastThreshold for 0
+func contrastThreshold(threshold float64) float64 {
+	if threshold == 0 {
+		return defaultContrastThreshold
+	}
+	return threshold
+}
+
+// Luminance returns the relative luminance of a 0-1 sRGB color, from 0 for
+// black to 1 for white, as WCAG defines it for contrast ratios
+func Luminance(color [3]float64) float64 {
+	var linear [3]float64
+	for i, c := range color {
+		if c <= 0.04045 {
+			linear[i] = c / 12.92
+		} else {
+			linear[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	return 0.2126*linear[0] + 0.7152*linear[1] + 0.0722*linear[2]
+}
+
+// RenderStringAutoContrast draws text like RenderString in black or white,
+// whichever is easier to read over bg
+func (font *Font) RenderStringAutoContrast(text string, bg [3]float64) *sdl.Surface {
+	return font.RenderStringContrastPair(text, bg, [3]float64{0, 0, 0}, [3]float64{1, 1, 1})
+}
+
+// RenderStringContrastPair is RenderStringAutoContrast choosing between dark
+// and light instead of black and white
+func (font *Font) RenderStringContrastPair(text string, bg, dark, light [3]float64) *sdl.Surface {
+	color := light
+	if Luminance(bg) > contrastThreshold(font.ContrastThreshold) {
+		color = dark
+	}
+	return font.RenderString(text, color[0], color[1], color[2])
+}
+
+// the most pixels along each side of a rect SampleLuminance reads
+const luminanceSamples = 16
+
+// SampleLuminance returns the average Luminance of the pixels of dst in rect,
+// reading at most a 16x16 grid of them spread evenly over it. A rect that
+// doesn't overlap dst counts as black.
+func SampleLuminance(dst *sdl.Surface, rect sdl.Rect) float64 {
+	area, ok := rect.Intersect(&sdl.Rect{W: dst.W, H: dst.H})
+	if !ok {
+		return 0
+	}
+	if dst.MustLock() {
+		dst.Lock()
+		defer dst.Unlock()
+	}
+	pixels := dst.Pixels()
+
+	stepX := (int(area.W) + luminanceSamples - 1) / luminanceSamples
+	stepY := (int(area.H) + luminanceSamples - 1) / luminanceSamples
+	total, count := 0.0, 0
+	for y := int(area.Y); y < int(area.Y+area.H); y += stepY {
+		for x := int(area.X); x < int(area.X+area.W); x += stepX {
+			r, g, b, _ := sdl.GetRGBA(getPixel(dst, pixels, x, y), dst.Format)
+			total += Luminance([3]float64{float64(r) / 255, float64(g) / 255, float64(b) / 255})
+			count++
+		}
+	}
+	return total / float64(count)
+}
+
+// ContrastPicker picks black or white text for whatever is already drawn
+// where the text goes, frame after frame. Once it has picked a color it
+// keeps it until the background's luminance is Hysteresis past Threshold the
+// other way, so a background that hovers around the threshold doesn't make
+// the text flicker. The zero value is ready to use, and each piece of text
+// needs its own.
+type ContrastPicker struct {
+	Threshold   float64 // background luminance above which dark text is picked, 0 for 0.179
+	Hysteresis  float64 // how far past Threshold the luminance must go to switch colors, 0 for 0.05
+	OutlineBand float64 // backgrounds with a luminance this close to Threshold also get an outline, 0 for never
+
+	dark, picked bool
+}
+
+// Pick samples the luminance of dst in rect and returns the text color to
+// draw there, and whether it needs an outline in the other color
+func (picker *ContrastPicker) Pick(dst *sdl.Surface, rect sdl.Rect) (color [3]float64, outline bool) {
+	hysteresis := picker.Hysteresis
+	if hysteresis == 0 {
+		hysteresis = 0.05
+	}
+	threshold := contrastThreshold(picker.Threshold)
+	lum := SampleLuminance(dst, rect)
+	switch {
+	case !picker.picked:
+		picker.dark, picker.picked = lum > threshold, true
+	case picker.dark && lum < threshold-hysteresis:
+		picker.dark = false
+	case !picker.dark && lum > threshold+hysteresis:
+		picker.dark = true
+	}
+
+	if !picker.dark {
+		color = [3]float64{1, 1, 1}
+	}
+	return color, math.Abs(lum-threshold) < picker.OutlineBand
+}
+
+// RenderStringContrastInto draws text onto dst with its top left corner at
+// x, y in the color picker picks for what's already there, with a 1px
+// outline in the other color when it asks for one
+func (font *Font) RenderStringContrastInto(dst *sdl.Surface, x, y int, text string, picker *ContrastPicker) {
+	layout := font.layout(text, LayoutOptions{})
+	color, outline := picker.Pick(dst, sdl.Rect{X: int32(x), Y: int32(y), W: int32(layout.width), H: int32(layout.height)})
+
+	all := func(GlyphPlacement) bool { return true }
+	if outline {
+		c := uint8(255 - color[0]*255)
+		tint := sdl.Color{R: c, G: c, B: c, A: 255}
+		for _, offset := range [...][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}} {
+			layout.compositeGlyphs(dst, x+offset[0], y+offset[1], tint, all)
+		}
+	}
+	c := uint8(color[0] * 255)
+	layout.compositeGlyphs(dst, x, y, sdl.Color{R: c, G: c, B: c, A: 255}, all)
+}
@@ -0,0 +1,45 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// inkColor returns the color of the first opaque pixel of surface
+func inkColor(surface *sdl.Surface) [4]uint8 {
+	for _, p := range rgba(surface) {
+		if p[3] == 255 {
+			return p
+		}
+	}
+	return [4]uint8{}
+}
+
+func TestContrastThreshold(t *testing.T) {
+	gray := [3]float64{0.5, 0.5, 0.5} // luminance about 0.21
+	black, white := [4]uint8{0, 0, 0, 255}, [4]uint8{255, 255, 255, 255}
+
+	font := MakeDefaultFont()
+	if c := inkColor(font.RenderStringAutoContrast("H", gray)); c != black {
+		t.Errorf("over gray with the default threshold the text is %v, want black", c)
+	}
+	other := font
+	other.ContrastThreshold = 0.5
+	if c := inkColor(other.RenderStringAutoContrast("H", gray)); c != white {
+		t.Errorf("over gray with threshold 0.5 the text is %v, want white", c)
+	}
+	if c := inkColor(font.RenderStringAutoContrast("H", gray)); c != black {
+		t.Error("another font's threshold changed this one's")
+	}
+
+	bg := newSurface(8, 8)
+	bg.FillRect(nil, sdl.MapRGBA(bg.Format, 128, 128, 128, 255))
+	rect := sdl.Rect{W: 8, H: 8}
+	if color, _ := (&ContrastPicker{}).Pick(bg, rect); color != [3]float64{} {
+		t.Errorf("the default picker picked %v over gray, want black", color)
+	}
+	if color, _ := (&ContrastPicker{Threshold: 0.5}).Pick(bg, rect); color != [3]float64{1, 1, 1} {
+		t.Errorf("a picker with threshold 0.5 picked %v over gray, want white", color)
+	}
+}
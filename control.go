@@ -0,0 +1,59 @@
+package font
+
+// ControlCharPolicy decides how non-printing ASCII (below 0x20, and DEL) is
+// drawn. Newlines and tabs keep their own handling and carriage returns are
+// always dropped, whatever the policy.
+type ControlCharPolicy int
+
+const (
+	ControlMissing ControlCharPolicy = iota // treat them like any other rune the font doesn't have
+	ControlSkip                             // drop them silently
+	ControlPicture                          // draw their Unicode control picture (␀ to ␟, ␡), if the font has it
+	ControlSpace                            // draw a space in their place
+)
+
+func isControl(char rune) bool {
+	return char < 0x20 && char != '\n' && char != '\t' && char != '\r' || char == 0x7F
+}
+
+// overstrike makes each '\b' in runes move the cursor back over the glyph
+// before it, so the next glyph is drawn on top of that one. shaped is what's
+// drawn for each rune.
+func (font *Font) overstrike(runes []rune, shaped []ShapedGlyph) {
+	for i, char := range runes {
+		if char != '\b' || shaped[i].Glyph == ligatureTail {
+			continue
+		}
+		back := 0
+		if prev := i - 1; prev >= 0 {
+			for prev > 0 && shaped[prev].Glyph == ligatureTail {
+				prev--
+			}
+			back = font.shapedAdvance(shaped[prev])
+		}
+		shaped[i] = ShapedGlyph{Glyph: '\b', Cluster: i, Advance: -back, HasAdvance: true}
+	}
+}
+
+// displayRune returns the rune drawn for char, and false if it draws nothing at all
+func (font *Font) displayRune(char rune) (rune, bool) {
+	if char == '\r' || char == '\b' && font.Overstrike {
+		return 0, false
+	}
+	if !isControl(char) {
+		return font.alias(char), true
+	}
+
+	switch font.ControlChars {
+	case ControlSkip:
+		return 0, false
+	case ControlPicture:
+		if char == 0x7F {
+			return '␡', true
+		}
+		return '␀' + char, true
+	case ControlSpace:
+		return ' ', true
+	}
+	return char, true
+}
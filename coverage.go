@@ -0,0 +1,89 @@
+package font
+
+// CoverageSource is how a font draws a rune of text
+type CoverageSource int
+
+const (
+	CoverageGlyph   CoverageSource = iota // with the rune's own glyph
+	CoverageAlias                         // with another rune's glyph, through Aliases
+	CoverageControl                       // as a control character: tabs by the layout, others by the ControlChars policy
+	CoverageMissing                       // not at all, rendering skips it
+)
+
+// MissingRune is a rune a CoverageReport found the font can't draw
+type MissingRune struct {
+	Rune  rune
+	Count int // how many times it appears, in all the texts
+	Text  int // which of the texts passed to CoverageOf it first appears in, 0 for Coverage
+	First int // the rune index in that text where it first appears
+}
+
+// CoverageReport is what Coverage and CoverageOf found about how well a font
+// covers some text. Newlines aren't counted, and shortcodes count as the
+// rune they stand for.
+type CoverageReport struct {
+	Supported   int                     // how many runes of the text the font draws, counting repeats
+	Unsupported int                     // how many it doesn't
+	Missing     []MissingRune           // each rune the font doesn't draw, in the order they first appear
+	Sources     map[rune]CoverageSource // how each distinct rune of the text is drawn, missing ones included
+}
+
+// Coverage reports which runes of text the font can draw and how, and which
+// it can't, for checking translations against the font before they ship
+func (font *Font) Coverage(text string) CoverageReport {
+	return font.CoverageOf([]string{text})
+}
+
+// CoverageOf is Coverage over many texts at once, such as every string of a
+// translation file
+func (font *Font) CoverageOf(texts []string) CoverageReport {
+	report := CoverageReport{Sources: make(map[rune]CoverageSource)}
+	missing := make(map[rune]int) // index in report.Missing
+	for t, text := range texts {
+		runes, source := []rune(text), []int(nil)
+		if len(font.Shortcodes) > 0 {
+			runes, source = font.expandShortcodes(runes)
+		}
+		for i, char := range runes {
+			if char == '\n' {
+				continue
+			}
+			src, seen := report.Sources[char]
+			if !seen {
+				src = font.coverageSource(char)
+				report.Sources[char] = src
+			}
+			if src != CoverageMissing {
+				report.Supported++
+				continue
+			}
+
+			report.Unsupported++
+			if m, ok := missing[char]; ok {
+				report.Missing[m].Count++
+				continue
+			}
+			first := i
+			if source != nil {
+				first = source[i]
+			}
+			missing[char] = len(report.Missing)
+			report.Missing = append(report.Missing, MissingRune{Rune: char, Count: 1, Text: t, First: first})
+		}
+	}
+	return report
+}
+
+// coverageSource returns how the font draws char
+func (font *Font) coverageSource(char rune) CoverageSource {
+	if _, ok := font.advance(char); !ok {
+		return CoverageMissing
+	}
+	if char < 0x20 || char == 0x7F {
+		return CoverageControl
+	}
+	if _, ok := font.Aliases[char]; ok {
+		return CoverageAlias
+	}
+	return CoverageGlyph
+}
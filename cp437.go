@@ -0,0 +1,44 @@
+package font
+
+import "github.com/veandco/go-sdl2/img"
+
+// CP437CharSet is the 256 characters of IBM PC code page 437 in code page
+// order, the layout of most classic ROM and console fonts, as Unicode
+const CP437CharSet = "\x00☺☻♥♦♣♠•◘○◙♂♀♪♫☼" + // 0x00, drawn as a blank cell in most fonts
+	"►◄↕‼¶§▬↨↑↓→←∟↔▲▼" +
+	" !\"#$%&'()*+,-./" +
+	"0123456789:;<=>?" +
+	"@ABCDEFGHIJKLMNO" +
+	"PQRSTUVWXYZ[\\]^_" +
+	"`abcdefghijklmno" +
+	"pqrstuvwxyz{|}~⌂" +
+	"ÇüéâäàåçêëèïîìÄÅ" +
+	"ÉæÆôöòûùÿÖÜ¢£¥₧ƒ" +
+	"áíóúñÑªº¿⌐¬½¼¡«»" +
+	"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐" +
+	"└┴┬├─┼╞╟╚╔╩╦╠═╬╧" +
+	"╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" +
+	"αßΓπΣσµτΦΘΩδ∞φε∩" +
+	"≡±≥≤⌠⌡÷≈°∙·√ⁿ²■\u00a0" // 0xFF is a no-break space
+
+// LoadCP437Font creates a monospace Font from an atlas of 16x16 cells in code
+// page 437 order, like a ROM font dump. Cells are cellW by cellH and packed
+// edge to edge, and glyphs are drawn without extra spacing since ROM glyphs
+// carry their own. Text is plain Unicode: '♥', '║' and 'é' draw from the
+// cells CP437 puts them in.
+func LoadCP437Font(path string, cellW, cellH int) (Font, error) {
+	atlas, err := img.Load(path)
+	if err != nil {
+		return Font{}, err
+	}
+	widths := make([]int, 256)
+	for i := range widths {
+		widths[i] = cellW
+	}
+	font := fontFromAtlas(atlas, 16, CP437CharSet, widths)
+	font.CharSize = [2]int{cellW, cellH}
+	font.Packed = true
+	font.LetterPad = 0
+	font.NewlinePad = 0
+	return font, nil
+}
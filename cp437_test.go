@@ -0,0 +1,74 @@
+package font
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+)
+
+// romGlyphs are a few glyphs of the IBM PC's 8x8 ROM font, a byte per row
+// with the leftmost pixel in the high bit, by code page 437 position
+var romGlyphs = map[int][8]uint8{
+	0x03: {0x6C, 0xFE, 0xFE, 0xFE, 0x7C, 0x38, 0x10, 0x00}, // ♥
+	0x41: {0x30, 0x78, 0xCC, 0xCC, 0xFC, 0xCC, 0xCC, 0x00}, // A
+	0x82: {0x1C, 0x00, 0x78, 0xCC, 0xFC, 0xC0, 0x78, 0x00}, // é
+	0xBA: {0x36, 0x36, 0x36, 0x36, 0x36, 0x36, 0x36, 0x36}, // ║
+	0xBB: {0x00, 0x00, 0xFE, 0x06, 0xF6, 0x36, 0x36, 0x36}, // ╗
+	0xBC: {0x36, 0x36, 0xF6, 0x06, 0xFE, 0x00, 0x00, 0x00}, // ╝
+	0xC8: {0x36, 0x36, 0x37, 0x30, 0x3F, 0x00, 0x00, 0x00}, // ╚
+	0xC9: {0x00, 0x00, 0x3F, 0x30, 0x37, 0x36, 0x36, 0x36}, // ╔
+	0xCD: {0x00, 0x00, 0xFF, 0x00, 0xFF, 0x00, 0x00, 0x00}, // ═
+}
+
+// writeROMFont writes a 16x16 grid of 8x8 cells holding romGlyphs, white on
+// transparent, to a PNG in dir
+func writeROMFont(t *testing.T, dir string) string {
+	t.Helper()
+	atlas := image.NewNRGBA(image.Rect(0, 0, 128, 128))
+	for code, rows := range romGlyphs {
+		for y, row := range rows {
+			for x := 0; x < 8; x++ {
+				if row&(0x80>>x) != 0 {
+					atlas.SetNRGBA(code%16*8+x, code/16*8+y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+				}
+			}
+		}
+	}
+	path := filepath.Join(dir, "rom.png")
+	writePNG(t, path, atlas)
+	return path
+}
+
+func TestCP437Font(t *testing.T) {
+	font, err := LoadCP437Font(writeROMFont(t, t.TempDir()), 8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// box drawing and accented letters come from their code page cells,
+	// and € isn't in code page 437 so it's skipped
+	text := "╔═══╗\n║A♥é║€\n╚═══╝"
+	want := [][]int{
+		{0xC9, 0xCD, 0xCD, 0xCD, 0xBB},
+		{0xBA, 0x41, 0x03, 0x82, 0xBA},
+		{0xC8, 0xCD, 0xCD, 0xCD, 0xBC},
+	}
+	surface := font.RenderString(text, 1, 1, 1)
+	if surface.W != 40 || surface.H != 24 {
+		t.Fatalf("RenderString is %dx%d, want 40x24 with no spacing", surface.W, surface.H)
+	}
+	pixels := rgba(surface)
+	for line, codes := range want {
+		for col, code := range codes {
+			rows := romGlyphs[code]
+			for y := 0; y < 8; y++ {
+				for x := 0; x < 8; x++ {
+					ink := pixels[(line*8+y)*40+col*8+x][3] > 0
+					if want := rows[y]&(0x80>>x) != 0; ink != want {
+						t.Fatalf("line %d column %d: pixel %d,%d of cell %#x has ink %t, want %t", line, col, x, y, code, ink, want)
+					}
+				}
+			}
+		}
+	}
+}
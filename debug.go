@@ -0,0 +1,46 @@
+package font
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// colors used by RenderDebug
+var (
+	DebugGlyphColor      = sdl.Color{R: 255, G: 0, B: 0, A: 255}   // glyph source rect boundary
+	DebugLetterPadColor  = sdl.Color{R: 0, G: 255, B: 0, A: 160}   // gap left by LetterPad after each glyph
+	DebugLineColor       = sdl.Color{R: 0, G: 128, B: 255, A: 255} // box around each line
+	DebugNewlinePadColor = sdl.Color{R: 255, G: 255, B: 0, A: 96}  // gap left by NewlinePad between lines
+)
+
+// RenderDebug renders text like RenderStringAligned and draws the layout on
+// top of it: the bounds of every glyph, the LetterPad gap after it, each line's
+// box, and the NewlinePad gap between lines, each in its own Debug*Color
+func (font *Font) RenderDebug(text string, opts LayoutOptions, r, g, b float64) *sdl.Surface {
+	layout := font.layout(text, opts)
+	surface := layout.Render(r, g, b)
+
+	fill := func(rect sdl.Rect, color sdl.Color) {
+		if rect.W > 0 && rect.H > 0 {
+			surface.FillRect(&rect, sdl.MapRGBA(surface.Format, color.R, color.G, color.B, color.A))
+		}
+	}
+	outline := func(rect sdl.Rect, color sdl.Color) {
+		fill(sdl.Rect{X: rect.X, Y: rect.Y, W: rect.W, H: 1}, color)
+		fill(sdl.Rect{X: rect.X, Y: rect.Y + rect.H - 1, W: rect.W, H: 1}, color)
+		fill(sdl.Rect{X: rect.X, Y: rect.Y, W: 1, H: rect.H}, color)
+		fill(sdl.Rect{X: rect.X + rect.W - 1, Y: rect.Y, W: 1, H: rect.H}, color)
+	}
+
+	for i := range layout.wrap.Lines {
+		box := layout.LineRect(i)
+		outline(box, DebugLineColor)
+		if i < len(layout.wrap.Lines)-1 {
+			fill(sdl.Rect{X: 0, Y: box.Y + box.H, W: int32(layout.width), H: int32(font.NewlinePad)}, DebugNewlinePadColor)
+		}
+	}
+
+	for _, glyph := range layout.glyphs {
+		outline(glyph.Dst, DebugGlyphColor)
+		fill(sdl.Rect{X: glyph.Dst.X + glyph.Dst.W, Y: glyph.Dst.Y, W: int32(font.LetterPad), H: glyph.Dst.H}, DebugLetterPadColor)
+	}
+
+	return surface
+}
@@ -0,0 +1,118 @@
+package font
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// SetDPIScale makes every render and measurement of the font scale times as
+// big, for high DPI displays, by swapping in an enlarged atlas and metrics.
+// The scale is rounded to a whole number of at least 1, since a pixel font
+// scaled by a fraction gets strokes of uneven widths, and lowered if need be
+// so the enlarged atlas is no wider or taller than MaxScaledSize. The atlas is
+// enlarged with nearest neighbour scaling, or taken from SmoothScales when it
+// has generated that scale. Setting the scale back to 1 restores the font as
+// it was, so change its metrics before setting a scale rather than after.
+func (font *Font) SetDPIScale(scale float64) {
+	n := max(int(math.Round(scale)), 1)
+	base := font.dpiBase
+	if base == nil {
+		base = font
+	}
+	n = max(min(n, MaxScaledSize/max(int(base.Atlas.W), int(base.Atlas.H), 1)), 1)
+	if n == font.DPIScale() {
+		return
+	}
+	if font.dpiBase == nil {
+		unscaled := *font
+		unscaled.numberCache = nil // freed below
+		base = &unscaled
+	}
+
+	font.ClearNumberCache()
+	if font.dpiBase != nil && font.Atlas != base.Atlas {
+		font.Atlas.Free() // the enlarged atlas the last call made
+	}
+	if n == 1 {
+		*font = *base
+		return
+	}
+	*font = base.scaled(n)
+	font.dpiBase = base
+}
+
+// DPIScale returns the scale SetDPIScale set, 1 by default
+func (font *Font) DPIScale() int {
+	if font.dpiBase == nil {
+		return 1
+	}
+	return font.dpiScale
+}
+
+// scaled returns a copy of the font with its atlas and metrics n times as big
+func (font *Font) scaled(n int) Font {
+	scaled := *font
+	scaled.numberCache = nil
+	scaled.smoothAtlases = nil
+	scaled.dpiScale = n
+
+	scaled.Atlas = newSurface(int(font.Atlas.W)*n, int(font.Atlas.H)*n)
+	if smooth, ok := font.smoothAtlases[n]; ok {
+		smooth.SetBlendMode(sdl.BLENDMODE_NONE)
+		smooth.Blit(nil, scaled.Atlas, nil)
+	} else {
+		white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+		enlarge(font.Atlas, sdl.Rect{W: font.Atlas.W, H: font.Atlas.H}, scaled.Atlas, 0, 0, n, white)
+	}
+
+	scaleInts := func(values []int) []int {
+		if values == nil {
+			return nil
+		}
+		out := make([]int, len(values))
+		for i, v := range values {
+			out[i] = v * n
+		}
+		return out
+	}
+	scaled.CharSize = [2]int{font.CharSize[0] * n, font.CharSize[1] * n}
+	scaled.CellPad = font.cellPad() * n
+	scaled.NewlinePad *= n
+	scaled.LetterPad *= n
+	scaled.WordSpacing *= n
+	scaled.CharWidths = scaleInts(font.CharWidths)
+	scaled.DefaultCharWidth *= n
+	scaled.Bearings = scaleInts(font.Bearings)
+	scaled.Advances = scaleInts(font.Advances)
+	if font.AdvanceOverride != nil {
+		scaled.AdvanceOverride = make(map[rune]int, len(font.AdvanceOverride))
+		for char, adv := range font.AdvanceOverride {
+			scaled.AdvanceOverride[char] = adv * n
+		}
+	}
+	if font.HangingPunctuation != nil {
+		scaled.HangingPunctuation = make(map[rune]int, len(font.HangingPunctuation))
+		for char, hang := range font.HangingPunctuation {
+			scaled.HangingPunctuation[char] = hang * n
+		}
+	}
+	if font.Combining != nil {
+		scaled.Combining = make(map[rune][2]int, len(font.Combining))
+		for char, offset := range font.Combining {
+			scaled.Combining[char] = [2]int{offset[0] * n, offset[1] * n}
+		}
+	}
+	if font.Rects != nil {
+		scaled.Rects = make([]GlyphSource, len(font.Rects))
+		for i, glyph := range font.Rects {
+			r := glyph.Rect
+			scaled.Rects[i] = GlyphSource{
+				Rect:    sdl.Rect{X: r.X * int32(n), Y: r.Y * int32(n), W: r.W * int32(n), H: r.H * int32(n)},
+				Advance: glyph.Advance * n,
+				Top:     glyph.Top * n,
+			}
+		}
+	}
+	return scaled
+}
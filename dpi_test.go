@@ -0,0 +1,47 @@
+package font
+
+import "testing"
+
+func TestSetDPIScale(t *testing.T) {
+	font := MakeDefaultFont()
+	atlas := font.Atlas
+	want := font.RenderString("Hi", 1, 1, 1)
+	w, h := font.Measure("Hi")
+
+	for _, scale := range []float64{2, 3, 2.4, 4} {
+		font.SetDPIScale(scale)
+		n := font.DPIScale()
+		if sw, sh := font.Measure("Hi"); sw != w*n || sh != h*n {
+			t.Errorf("at scale %v Measure is %dx%d, want %dx%d", scale, sw, sh, w*n, h*n)
+		}
+		if font.Atlas.W != atlas.W*int32(n) {
+			t.Errorf("at scale %v the atlas is %d wide, want %d", scale, font.Atlas.W, atlas.W*int32(n))
+		}
+	}
+	// every pixel of the atlas becomes n by n, whatever n is
+	small := rgba(want)
+	for _, n := range []int{2, 3} {
+		font.SetDPIScale(float64(n))
+		large := font.RenderString("Hi", 1, 1, 1)
+		for i, p := range rgba(large) {
+			x, y := i%int(large.W), i/int(large.W)
+			if c := small[y/n*int(want.W)+x/n]; p != c {
+				t.Fatalf("at scale %d pixel %d,%d is %v, want %v", n, x, y, p, c)
+			}
+		}
+	}
+
+	font.SetDPIScale(1)
+	if font.Atlas != atlas || font.DPIScale() != 1 {
+		t.Fatal("scale 1 didn't restore the original atlas")
+	}
+	assertSamePixels(t, "restored", font.RenderString("Hi", 1, 1, 1), want)
+}
+
+func TestSetDPIScaleBounded(t *testing.T) {
+	font := Font{Atlas: newSurface(4096, 1), GridWidth: 1, CharSize: [2]int{1, 1}, CharSet: "a", CharWidths: []int{1}}
+	font.SetDPIScale(100)
+	if font.DPIScale() != 4 || font.Atlas.W != MaxScaledSize {
+		t.Errorf("scale 100 of a 4096px atlas gave scale %d and a %dpx atlas, want 4 and %d", font.DPIScale(), font.Atlas.W, MaxScaledSize)
+	}
+}
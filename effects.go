@@ -0,0 +1,81 @@
+package font
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// Effect is one pass of text drawn by RenderEffects. A fill is a pass with
+// no offset or thickness, a drop shadow one with an offset and an outline one
+// with a thickness. Passes are drawn back to front, so
+//
+//	[]Effect{
+//		{Offset: [2]int{1, 1}, Color: sdl.Color{A: 128}},    // shadow
+//		{Thickness: 1, Color: sdl.Color{B: 128, A: 255}},    // outline
+//		{Color: sdl.Color{R: 255, G: 255, B: 255, A: 255}}, // fill
+//	}
+//
+// draws white text with a dark blue outline and a half transparent shadow.
+type Effect struct {
+	Offset    [2]int    // how far the pass is drawn right and down of the text
+	Thickness int       // how many pixels the glyphs grow by in every direction, squarely, so corners stay sharp
+	Color     sdl.Color // a translucent color draws the whole pass translucent, however much of it overlaps
+}
+
+// EffectBounds returns the box RenderEffects draws into, relative to the text
+// origin: the layout's box grown to cover every pass's offset and thickness.
+// Its X and Y are how far the passes reach above and left of the origin, so
+// at most 0, and its W and H are the size of the surface RenderEffects
+// returns, which is no wider than opts.MaxSurfaceWidth.
+func (layout *Layout) EffectBounds(effects []Effect) sdl.Rect {
+	left, top, right, bottom := 0, 0, layout.width, layout.height
+	for _, effect := range effects {
+		grow := max(effect.Thickness, 0)
+		left = min(left, effect.Offset[0]-grow)
+		top = min(top, effect.Offset[1]-grow)
+		right = max(right, layout.width+effect.Offset[0]+grow)
+		bottom = max(bottom, layout.height+effect.Offset[1]+grow)
+	}
+	return sdl.Rect{X: int32(left), Y: int32(top), W: int32(layout.capWidth(0, right-left)), H: int32(bottom - top)}
+}
+
+// RenderEffects draws the layout once per effect, back to front, onto a
+// surface sized to EffectBounds, with the text origin at minus the bounds' X
+// and Y. Line backgrounds are drawn behind every pass, under the text's box.
+func (layout *Layout) RenderEffects(effects []Effect) *sdl.Surface {
+	bounds := layout.EffectBounds(effects)
+	x, y := -int(bounds.X), -int(bounds.Y)
+	surface := layout.font.outputSurface(int(bounds.W), int(bounds.H))
+	layout.fillBackgrounds(surface, x, y)
+
+	// each pass is drawn in white onto a mask first, so overlapping copies
+	// of a thick outline don't add up to more than the pass's alpha
+	mask := newSurface(int(bounds.W), int(bounds.H))
+	defer mask.Free()
+	all := func(GlyphPlacement) bool { return true }
+	white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	for _, effect := range effects {
+		mask.FillRect(nil, 0)
+		grow := max(effect.Thickness, 0)
+		for dy := -grow; dy <= grow; dy++ {
+			for dx := -grow; dx <= grow; dx++ {
+				layout.compositeGlyphs(mask, x+effect.Offset[0]+dx, y+effect.Offset[1]+dy, white, all)
+			}
+		}
+		if surface.MustLock() {
+			surface.Lock()
+		}
+		compositeGlyph(mask, sdl.Rect{W: bounds.W, H: bounds.H}, surface, 0, 0, effect.Color)
+		if surface.MustLock() {
+			surface.Unlock()
+		}
+	}
+	return surface
+}
+
+// RenderStringEffects lays text out like RenderStringAligned and draws it
+// with RenderEffects
+func (font *Font) RenderStringEffects(text string, opts LayoutOptions, effects []Effect) (*sdl.Surface, error) {
+	layout, err := font.Layout(text, opts)
+	if err != nil {
+		return nil, err
+	}
+	return layout.RenderEffects(effects), nil
+}
@@ -0,0 +1,67 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestEffectsExtents(t *testing.T) {
+	font := MakeDefaultFont()
+	layout, err := font.Layout("Hi!", LayoutOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shadow := sdl.Color{R: 255, A: 255}
+	outline := sdl.Color{B: 255, A: 255}
+	fill := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	effects := []Effect{
+		{Offset: [2]int{2, 3}, Color: shadow},
+		{Thickness: 1, Color: outline},
+		{Color: fill},
+	}
+
+	// the outline reaches 1px up and left of the origin, the shadow 2px
+	// right and 3px down of the box, past the outline's 1px
+	w, h := layout.Size()
+	bounds := layout.EffectBounds(effects)
+	if want := (sdl.Rect{X: -1, Y: -1, W: int32(w + 3), H: int32(h + 4)}); bounds != want {
+		t.Errorf("EffectBounds = %v, want %v", bounds, want)
+	}
+	surface := layout.RenderEffects(effects)
+	if surface.W != bounds.W || surface.H != bounds.H {
+		t.Errorf("RenderEffects is %dx%d, want the bounds' %dx%d", surface.W, surface.H, bounds.W, bounds.H)
+	}
+
+	rendered := layout.Render(1, 1, 1)
+	plain := rgba(rendered)
+	pixels := rgba(surface)
+	at := func(x, y int) [4]uint8 { return pixels[y*int(surface.W)+x] }
+	for i, p := range plain {
+		if p[3] == 0 {
+			continue
+		}
+		// the fill is drawn on top, at the text origin
+		x, y := i%w+1, i/w+1
+		if c := at(x, y); c != [4]uint8{255, 255, 255, 255} {
+			t.Fatalf("fill pixel %d,%d is %v", x, y, c)
+		}
+		// nothing of the shadow is cut off
+		if c := at(x+2, y+3); c[3] != 255 {
+			t.Fatalf("shadow pixel %d,%d is %v", x+2, y+3, c)
+		}
+	}
+	first, last, _ := inkColumns(rendered)
+	if efirst, elast, _ := inkColumns(surface); efirst != first || elast != last+3 {
+		t.Errorf("effects ink columns %d to %d, want %d to %d", efirst, elast, first, last+3)
+	}
+	counts := map[[4]uint8]int{}
+	for _, p := range pixels {
+		counts[p]++
+	}
+	for name, c := range map[string]sdl.Color{"shadow": shadow, "outline": outline} {
+		if counts[[4]uint8{c.R, c.G, c.B, c.A}] == 0 {
+			t.Errorf("no pixel of the %s shows", name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package font
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// FontSource is a font embedded in Go source by ExportGoSource: its atlas
+// as PNG data and everything else about it
+type FontSource struct {
+	PNG  []byte
+	Font Font // without an atlas
+}
+
+// surfaceImage copies a surface's pixels into an image
+func surfaceImage(surface *sdl.Surface) *image.NRGBA {
+	if surface.MustLock() {
+		surface.Lock()
+		defer surface.Unlock()
+	}
+	pixels := surface.Pixels()
+	out := image.NewNRGBA(image.Rect(0, 0, int(surface.W), int(surface.H)))
+	for y := 0; y < int(surface.H); y++ {
+		for x := 0; x < int(surface.W); x++ {
+			r, g, b, a := sdl.GetRGBA(getPixel(surface, pixels, x, y), surface.Format)
+			out.SetNRGBA(x, y, color.NRGBA{R: r, G: g, B: b, A: a})
+		}
+	}
+	return out
+}
+
+// imageSurface copies an image's pixels into a new surface
+func imageSurface(src image.Image) *sdl.Surface {
+	bounds := src.Bounds()
+	surface := newSurface(bounds.Dx(), bounds.Dy())
+	pixels := surface.Pixels()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			setPixel(surface, pixels, x-bounds.Min.X, y-bounds.Min.Y, sdl.MapRGBA(surface.Format, c.R, c.G, c.B, c.A))
+		}
+	}
+	return surface
+}
+
+// Load decodes the embedded atlas and returns the font with it
+func (src *FontSource) Load() (Font, error) {
+	decoded, err := png.Decode(bytes.NewReader(src.PNG))
+	if err != nil {
+		return Font{}, fmt.Errorf("font source: %w", err)
+	}
+	font := src.Font
+	font.Atlas = imageSurface(decoded)
+	return font, nil
+}
+
+// ExportGoSource generates a Go file for package pkg that declares varName as
+// a *FontSource holding the font, atlas and all, so a program can carry it
+// without any asset files. varName.Load() gives back a font that renders
+// exactly like this one. Pre-rendered RenderNumber glyphs aren't exported.
+func ExportGoSource(f *Font, pkg, varName string) ([]byte, error) {
+	var atlas bytes.Buffer
+	if err := png.Encode(&atlas, surfaceImage(f.Atlas)); err != nil {
+		return nil, err
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by font.ExportGoSource. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if f.Rects != nil {
+		src.WriteString("import (\n\tfont \"github.com/wosly2/tiny-font\"\n\t\"github.com/veandco/go-sdl2/sdl\"\n)\n\n")
+	} else {
+		src.WriteString("import font \"github.com/wosly2/tiny-font\"\n\n")
+	}
+
+	fmt.Fprintf(&src, "var %s = &font.FontSource{\n", varName)
+	fmt.Fprintf(&src, "PNG: []byte(%+q),\n", atlas.String())
+	src.WriteString("Font: font.Font{\n")
+	fmt.Fprintf(&src, "GridWidth: %d,\nGridHeight: %d,\nCharSize: %#v,\nCellPad: %d,\nPacked: %t,\n", f.GridWidth, f.GridHeight, f.CharSize, f.CellPad, f.Packed)
+	fmt.Fprintf(&src, "CharSet: %q,\nCharWidths: %#v,\nDefaultCharWidth: %d,\n", f.CharSet, f.CharWidths, f.DefaultCharWidth)
+	if f.Bearings != nil {
+		fmt.Fprintf(&src, "Bearings: %#v,\n", f.Bearings)
+	}
+	if f.Advances != nil {
+		fmt.Fprintf(&src, "Advances: %#v,\n", f.Advances)
+	}
+	fmt.Fprintf(&src, "NewlinePad: %d,\nLetterPad: %d,\nWordSpacing: %d,\nTabSpaces: %d,\n", f.NewlinePad, f.LetterPad, f.WordSpacing, f.TabSpaces)
+	if f.AdvanceOverride != nil {
+		fmt.Fprintf(&src, "AdvanceOverride: %#v,\n", f.AdvanceOverride)
+	}
+	if f.Combining != nil {
+		fmt.Fprintf(&src, "Combining: %#v,\n", f.Combining)
+	}
+	if f.Aliases != nil {
+		fmt.Fprintf(&src, "Aliases: %#v,\n", f.Aliases)
+	}
+	if f.Ligatures != nil {
+		fmt.Fprintf(&src, "Ligatures: %#v,\n", f.Ligatures)
+	}
+	if f.Shortcodes != nil {
+		fmt.Fprintf(&src, "Shortcodes: %#v,\n", f.Shortcodes)
+	}
+	if f.DoubleWidth != nil {
+		fmt.Fprintf(&src, "DoubleWidth: %#v,\n", f.DoubleWidth)
+	}
+	if f.HangingPunctuation != nil {
+		fmt.Fprintf(&src, "HangingPunctuation: %#v,\n", f.HangingPunctuation)
+	}
+	fmt.Fprintf(&src, "CollapseWhitespace: %t,\nCollapseNewlines: %t,\n", f.CollapseWhitespace, f.CollapseNewlines)
+	fmt.Fprintf(&src, "ControlChars: font.ControlCharPolicy(%d),\nOverstrike: %t,\n", f.ControlChars, f.Overstrike)
+	fmt.Fprintf(&src, "SurfaceFormat: %d,\nContrastThreshold: %#v,\n", f.SurfaceFormat, f.ContrastThreshold)
+	if f.CheckboxMarkers != nil {
+		fmt.Fprintf(&src, "CheckboxMarkers: %#v,\n", f.CheckboxMarkers)
+	}
+	if f.Rects != nil {
+		src.WriteString("Rects: []font.GlyphSource{\n")
+		for _, g := range f.Rects {
+			fmt.Fprintf(&src, "{Rect: sdl.Rect{X: %d, Y: %d, W: %d, H: %d}, Advance: %d, Top: %d},\n", g.Rect.X, g.Rect.Y, g.Rect.W, g.Rect.H, g.Advance, g.Top)
+		}
+		src.WriteString("},\n")
+	}
+	src.WriteString("},\n}\n")
+
+	return format.Source(src.Bytes())
+}
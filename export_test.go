@@ -0,0 +1,132 @@
+package font
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// exportMain renders text with the exported font and writes it to the PNG
+// file named by its first argument, and the fields exportFields formats to
+// the file named by its second
+const exportMain = `package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func main() {
+	font, err := exported.Load()
+	if err != nil {
+		panic(err)
+	}
+	surface := font.RenderString(` + "`" + exportText + "`" + `, 1, 0.5, 0.25)
+	out := image.NewNRGBA(image.Rect(0, 0, int(surface.W), int(surface.H)))
+	pixels := surface.Pixels()
+	for y := 0; y < int(surface.H); y++ {
+		for x := 0; x < int(surface.W); x++ {
+			i := y*int(surface.Pitch) + x*4
+			p := uint32(pixels[i]) | uint32(pixels[i+1])<<8 | uint32(pixels[i+2])<<16 | uint32(pixels[i+3])<<24
+			r, g, b, a := sdl.GetRGBA(p, surface.Format)
+			out.SetNRGBA(x, y, color.NRGBA{R: r, G: g, B: b, A: a})
+		}
+	}
+	file, err := os.Create(os.Args[1])
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, out); err != nil {
+		panic(err)
+	}
+	fields := fmt.Sprintf(` + "`" + exportFields + "`" + `, font.SurfaceFormat, font.ContrastThreshold, font.CheckboxMarkers)
+	if err := os.WriteFile(os.Args[2], []byte(fields), 0o644); err != nil {
+		panic(err)
+	}
+}
+`
+
+// exportFields formats the fields that don't change the pixels of
+// exportText, so they're compared separately
+const exportFields = "%d %v %q"
+
+const exportText = "Exported -> fonts\nrender the same :)"
+
+func TestExportGoSource(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	for _, test := range []struct {
+		name  string
+		rects bool
+	}{
+		{"grid", false},
+		{"rect table", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			font := MakeDefaultFont()
+			if test.rects {
+				font.Rects = font.RectTable()
+			}
+			font.Ligatures = map[string]rune{"->": '☺'}
+			font.Aliases = map[rune]rune{'é': 'e'}
+			font.LetterPad = 2
+			font.SurfaceFormat = sdl.PIXELFORMAT_ABGR8888
+			font.ContrastThreshold = 0.3
+			font.CheckboxMarkers = [][2]string{{"o", "x"}, {"[ ]", "[x]"}}
+
+			src, err := ExportGoSource(&font, "main", "exported")
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the program has to be in the module to import it; the
+			// underscore keeps ./... from seeing it
+			dir, err := os.MkdirTemp(moduleDir, "_export")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			for name, data := range map[string][]byte{"font.go": src, "main.go": []byte(exportMain)} {
+				if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			out, fields := filepath.Join(t.TempDir(), "out.png"), filepath.Join(t.TempDir(), "fields.txt")
+			cmd := exec.Command("go", "run", "-tags", "static", ".", out, fields)
+			cmd.Dir = dir
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("running the exported font: %v\n%s", err, output)
+			}
+
+			file, err := os.Open(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			img, err := png.Decode(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertSamePixels(t, "exported", imageSurface(img), font.RenderString(exportText, 1, 0.5, 0.25))
+
+			got, err := os.ReadFile(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fmt.Sprintf(exportFields, font.SurfaceFormat, font.ContrastThreshold, font.CheckboxMarkers); string(got) != want {
+				t.Errorf("the exported font's fields are %s, want %s", got, want)
+			}
+		})
+	}
+}
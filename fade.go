@@ -0,0 +1,78 @@
+package font
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// RenderStringScrollFade draws the part of text scrolled scroll pixels up
+// into a region regionHeight pixels tall, like a frame of scrolling credits.
+// Glyphs fade out evenly over the fade pixels at the top and bottom of the
+// region: each glyph is drawn with one alpha, from how far its middle is from
+// the nearest edge, so letters fade whole instead of being cut across.
+func (font *Font) RenderStringScrollFade(text string, scroll, regionHeight, fade int, r, g, b float64) *sdl.Surface {
+	layout := font.layout(text, LayoutOptions{})
+	surface := font.outputSurface(layout.width, regionHeight)
+
+	atlas := font.Atlas
+	if atlas.MustLock() {
+		atlas.Lock()
+		defer atlas.Unlock()
+	}
+	if surface.MustLock() {
+		surface.Lock()
+		defer surface.Unlock()
+	}
+
+	tint := sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+	layout.Walk(func(glyph GlyphPlacement) bool {
+		y := int(glyph.Dst.Y) - scroll
+		if y+int(glyph.Dst.H) <= 0 || y >= regionHeight {
+			return true
+		}
+		if fade > 0 {
+			middle := y + int(glyph.Dst.H)/2
+			tint.A = uint8(255 * min(max(float64(min(middle, regionHeight-middle))/float64(fade), 0), 1))
+		}
+		compositeGlyph(atlas, glyph.Src, surface, int(glyph.Dst.X), y, tint)
+		return true
+	})
+	return surface
+}
+
+// RenderStringFadeClip draws text clipped to a box maxWidth pixels wide with
+// the text scrolled scroll pixels left, as in a text field. Where text runs
+// past the right edge, the last fade pixels before it fade out instead of
+// cutting the glyphs off sharply, and the same goes for the left edge when
+// the text is scrolled. Text that fits is drawn as it is. Fading multiplies
+// the alpha the pixels already have.
+func (font *Font) RenderStringFadeClip(text string, maxWidth, scroll, fade int, r, g, b float64) *sdl.Surface {
+	layout := font.layout(text, LayoutOptions{})
+	surface := font.outputSurface(maxWidth, layout.height)
+	layout.composite(surface, -scroll, 0, sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255})
+
+	fadeLeft := scroll > 0
+	fadeRight := layout.width-scroll > maxWidth
+	if fade <= 0 || !fadeLeft && !fadeRight {
+		return surface
+	}
+	if surface.MustLock() {
+		surface.Lock()
+		defer surface.Unlock()
+	}
+	pixels := surface.Pixels()
+	for x := 0; x < maxWidth; x++ {
+		ramp := 1.0
+		if fadeLeft {
+			ramp = min(ramp, float64(x+1)/float64(fade+1))
+		}
+		if fadeRight {
+			ramp = min(ramp, float64(maxWidth-x)/float64(fade+1))
+		}
+		if ramp >= 1 {
+			continue
+		}
+		for y := 0; y < int(surface.H); y++ {
+			cr, cg, cb, ca := sdl.GetRGBA(getPixel(surface, pixels, x, y), surface.Format)
+			setPixel(surface, pixels, x, y, sdl.MapRGBA(surface.Format, cr, cg, cb, uint8(float64(ca)*ramp)))
+		}
+	}
+	return surface
+}
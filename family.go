@@ -0,0 +1,257 @@
+package font
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// RegularTag is the tag of the face other faces fall back to
+const RegularTag = "regular"
+
+// Family is a set of related faces of one typeface, such as regular, bold and small
+type Family struct {
+	Name  string
+	Faces map[string]*Font // faces by variant tag
+}
+
+// familyFile is the JSON definition read by LoadFamily
+type familyFile struct {
+	Name      string     `json:"name"`
+	CharSet   string     `json:"charset"`   // shared by every face unless it sets its own
+	GridWidth int        `json:"gridWidth"` // shared by every face unless it sets its own
+	Faces     []faceFile `json:"faces"`
+}
+
+type faceFile struct {
+	Tag        string            `json:"tag"`   // regular, bold, italic, small or any other variant name
+	Atlas      string            `json:"atlas"` // relative to the definition file
+	CharSet    string            `json:"charset,omitempty"`
+	GridWidth  int               `json:"gridWidth,omitempty"`
+	CellSize   [2]int            `json:"cellSize,omitempty"`
+	CellPad    *int              `json:"cellPad,omitempty"`   // 1 when left out, 0 for a packed atlas
+	Widths     []int             `json:"widths,omitempty"`    // the regular face's widths when left out
+	Glyphs     [][5]int          `json:"glyphs,omitempty"`    // x, y, w, h and advance of each glyph, instead of a grid and widths
+	Aliases    map[string]string `json:"aliases,omitempty"`   // characters drawn with another character's glyph, like {"Ο": "O"}
+	Ligatures  map[string]string `json:"ligatures,omitempty"` // sequences drawn as one character's glyph, like {"fi": "\uE000"}
+	LetterPad  *int              `json:"letterPad,omitempty"`
+	NewlinePad *int              `json:"newlinePad,omitempty"`
+}
+
+// LoadFamily loads every face of a family from a JSON definition file like
+//
+//	{
+//		"name": "Isometrica",
+//		"charset": " !\"#...",
+//		"gridWidth": 10,
+//		"faces": [
+//			{"tag": "regular", "atlas": "regular.png", "widths": [3, 1, 3, ...]},
+//			{"tag": "bold", "atlas": "bold.png"},
+//			{"tag": "small", "atlas": "small.png", "cellSize": [3, 6], "widths": [2, 1, ...]}
+//		]
+//	}
+//
+// Faces without widths use the regular face's. A face whose atlas isn't a grid,
+// such as one made by a packer, lists where each glyph is along with its
+// advance instead, as "glyphs": [[x, y, w, h, advance], ...].
+func LoadFamily(path string) (*Family, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var def familyFile
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("family %s: %w", path, err)
+	}
+	if len(def.Faces) == 0 {
+		return nil, fmt.Errorf("family %s: no faces", path)
+	}
+
+	var regularWidths []int
+	for _, face := range def.Faces {
+		if face.Tag == RegularTag {
+			regularWidths = face.Widths
+		}
+	}
+
+	family := &Family{Name: def.Name, Faces: make(map[string]*Font, len(def.Faces))}
+	for _, face := range def.Faces {
+		if _, ok := family.Faces[face.Tag]; ok {
+			family.free()
+			return nil, fmt.Errorf("family %s: duplicate face %q", path, face.Tag)
+		}
+		font, err := face.load(def, filepath.Dir(path), regularWidths)
+		if err != nil {
+			family.free()
+			return nil, fmt.Errorf("family %s: face %q: %w", path, face.Tag, err)
+		}
+		family.Faces[face.Tag] = font
+	}
+	return family, nil
+}
+
+// free frees the atlases of the faces loaded so far, for a family that's
+// never returned
+func (family *Family) free() {
+	for _, font := range family.Faces {
+		font.Atlas.Free()
+	}
+}
+
+// load builds the face's Font, filling in anything it leaves out from the family
+func (face faceFile) load(def familyFile, dir string, regularWidths []int) (*Font, error) {
+	charSet := face.CharSet
+	if charSet == "" {
+		charSet = def.CharSet
+	}
+	gridWidth := face.GridWidth
+	if gridWidth == 0 {
+		gridWidth = def.GridWidth
+	}
+	if face.Glyphs != nil {
+		return face.loadRects(charSet, dir)
+	}
+	widths := face.Widths
+	if widths == nil {
+		widths = regularWidths
+	}
+	if gridWidth <= 0 {
+		return nil, fmt.Errorf("no grid width")
+	}
+	if count := utf8.RuneCountInString(charSet); len(widths) != count {
+		return nil, fmt.Errorf("%d widths for %d characters", len(widths), count)
+	}
+
+	atlas, err := img.Load(filepath.Join(dir, face.Atlas))
+	if err != nil {
+		return nil, err
+	}
+	font := fontFromAtlas(atlas, gridWidth, charSet, widths)
+	if err := face.applyMetrics(&font); err != nil {
+		atlas.Free()
+		return nil, err
+	}
+	return &font, nil
+}
+
+// loadRects builds a face that lists a rect for each glyph
+func (face faceFile) loadRects(charSet string, dir string) (*Font, error) {
+	glyphs := make([]GlyphSource, len(face.Glyphs))
+	for i, g := range face.Glyphs {
+		glyphs[i] = GlyphSource{Rect: sdl.Rect{X: int32(g[0]), Y: int32(g[1]), W: int32(g[2]), H: int32(g[3])}, Advance: g[4]}
+	}
+	font, err := NewFontFromRects(filepath.Join(dir, face.Atlas), charSet, glyphs)
+	if err != nil {
+		return nil, err
+	}
+	if err := face.applyMetrics(&font); err != nil {
+		font.Atlas.Free()
+		return nil, err
+	}
+	return &font, nil
+}
+
+// applyMetrics overrides the default metrics with any the face sets and adds
+// its aliases, which must each point straight at a character the face has,
+// and ligatures
+func (face faceFile) applyMetrics(font *Font) error {
+	for from, to := range face.Aliases {
+		if utf8.RuneCountInString(from) != 1 || utf8.RuneCountInString(to) != 1 {
+			return fmt.Errorf("alias %q -> %q isn't between single characters", from, to)
+		}
+		if font.Aliases == nil {
+			font.Aliases = make(map[rune]rune)
+		}
+		char, _ := utf8.DecodeRuneInString(from)
+		target, _ := utf8.DecodeRuneInString(to)
+		font.Aliases[char] = target
+	}
+	for seq, to := range face.Ligatures {
+		if utf8.RuneCountInString(seq) < 2 || utf8.RuneCountInString(to) != 1 {
+			return fmt.Errorf("ligature %q -> %q isn't a sequence drawn as a single character", seq, to)
+		}
+		if font.Ligatures == nil {
+			font.Ligatures = make(map[string]rune)
+		}
+		glyph, _ := utf8.DecodeRuneInString(to)
+		font.Ligatures[seq] = glyph
+	}
+
+	if face.CellSize != [2]int{} {
+		font.CharSize = face.CellSize
+	}
+	if face.CellPad != nil {
+		font.CellPad, font.Packed = *face.CellPad, *face.CellPad == 0
+	}
+	if face.LetterPad != nil {
+		font.LetterPad = *face.LetterPad
+	}
+	if face.NewlinePad != nil {
+		font.NewlinePad = *face.NewlinePad
+	}
+	return errors.Join(font.aliasErrors()...)
+}
+
+// Face returns the face with a variant tag, or the regular face if the family doesn't have it
+func (family *Family) Face(tag string) *Font {
+	if font, ok := family.Faces[tag]; ok {
+		return font
+	}
+	return family.Faces[RegularTag]
+}
+
+// fontSetEntry is one font of a font set, a face definition or a bundle
+type fontSetEntry struct {
+	faceFile
+	Bundle string `json:"bundle,omitempty"` // a SaveBundle file relative to the definition file, instead of an atlas and metrics
+}
+
+// LoadFontSet loads several unrelated fonts, such as a game's UI, title and
+// mono fonts, from a JSON definition file like
+//
+//	{
+//		"ui": {"atlas": "ui.png", "charset": " !\"#...", "gridWidth": 10, "widths": [3, 1, 3, ...]},
+//		"title": {"atlas": "title.png", "charset": "ABC...", "gridWidth": 8, "cellSize": [9, 12], "widths": [8, 8, ...]},
+//		"mono": {"bundle": "mono.zip"}
+//	}
+//
+// returning them by name. Each font is defined like a face of LoadFamily,
+// except that it sets its charset and grid width itself, or names a bundle.
+func LoadFontSet(path string) (map[string]Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var def map[string]fontSetEntry
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("font set %s: %w", path, err)
+	}
+
+	fonts := make(map[string]Font, len(def))
+	for _, name := range slices.Sorted(maps.Keys(def)) { // so the same font fails first every time
+		entry := def[name]
+		var font *Font
+		if entry.Bundle != "" {
+			font, err = LoadBundle(filepath.Join(filepath.Dir(path), entry.Bundle))
+		} else {
+			font, err = entry.load(familyFile{}, filepath.Dir(path), nil)
+		}
+		if err != nil {
+			// the fonts already loaded are never returned, so nothing else can free them
+			for _, loaded := range fonts {
+				loaded.Atlas.Free()
+			}
+			return nil, fmt.Errorf("font set %s: font %q: %w", path, name, err)
+		}
+		fonts[name] = *font
+	}
+	return fonts, nil
+}
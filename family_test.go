@@ -0,0 +1,82 @@
+package font
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/img"
+)
+
+func TestLoadFontSet(t *testing.T) {
+	dir := t.TempDir()
+	font := MakeDefaultFont()
+	if err := font.SaveBundle(filepath.Join(dir, "ui.zip")); err != nil {
+		t.Fatal(err)
+	}
+	write := func(def string) string {
+		path := filepath.Join(dir, "set.json")
+		if err := os.WriteFile(path, []byte(def), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	fonts, err := LoadFontSet(write(`{"title": {"bundle": "ui.zip"}, "ui": {"bundle": "ui.zip"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fonts) != 2 {
+		t.Fatalf("LoadFontSet loaded %d fonts, want 2", len(fonts))
+	}
+	ui := fonts["ui"]
+	assertSamePixels(t, "ui", ui.RenderString("Hi", 1, 1, 1), font.RenderString("Hi", 1, 1, 1))
+
+	// the fonts loaded before the broken one are freed, not returned
+	fonts, err = LoadFontSet(write(`{"title": {"bundle": "ui.zip"}, "ui": {"bundle": "missing.zip"}}`))
+	if err == nil || !strings.Contains(err.Error(), `font "ui"`) {
+		t.Errorf("LoadFontSet error = %v, want one naming font \"ui\"", err)
+	}
+	if fonts != nil {
+		t.Errorf("LoadFontSet returned %d fonts along with its error", len(fonts))
+	}
+}
+
+func TestLoadFamilyErrors(t *testing.T) {
+	dir := t.TempDir()
+	font := MakeDefaultFont()
+	if err := img.SavePNG(font.Atlas, filepath.Join(dir, "regular.png")); err != nil {
+		t.Fatal(err)
+	}
+	regular := map[string]any{"tag": RegularTag, "atlas": "regular.png", "widths": font.CharWidths}
+	for _, test := range []struct {
+		name string
+		face map[string]any
+		want string
+	}{
+		{"missing atlas", map[string]any{"tag": "bold", "atlas": "bold.png"}, `face "bold"`},
+		{"bad alias", map[string]any{"tag": "bold", "atlas": "regular.png", "aliases": map[string]string{"é": "€"}}, `face "bold"`},
+		{"bad rect face alias", map[string]any{"tag": "bold", "atlas": "regular.png", "glyphs": [][5]int{{0, 0, 1, 1, 2}}, "charset": "a", "aliases": map[string]string{"b": "c"}}, `face "bold"`},
+		{"duplicate face", regular, "duplicate face"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(map[string]any{"charset": font.CharSet, "gridWidth": font.GridWidth, "faces": []any{regular, test.face}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, "family.json")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			family, err := LoadFamily(path)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("LoadFamily error = %v, want one mentioning %s", err, test.want)
+			}
+			if family != nil {
+				t.Errorf("LoadFamily returned %d faces along with its error", len(family.Faces))
+			}
+		})
+	}
+}
@@ -1,48 +1,291 @@
 package font
 
 import (
+	"errors"
+	"fmt"
 	"log"
-	"strings"
+	"maps"
+	"slices"
+	"unicode/utf8"
 
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 type Font struct {
-	Atlas      *sdl.Surface
-	GridWidth  int
-	CharSize   [2]int // Width and height of each character cell (excluding 1px padding)
-	CharSet    string // String containing all supported characters in order matching atlas
-	CharWidths []int  // Width of each character (indices match CharSet)
-	NewlinePad int    // Extra vertical padding between lines
-	LetterPad  int    // Extra horizontal padding between characters
-}
-
-// gets the glyph rect from the atlas
-func (font *Font) loadGlyph(char rune) sdl.Rect {
-	index := strings.IndexRune(font.CharSet, char)
+	Atlas            *sdl.Surface
+	GridWidth        int
+	GridHeight       int    // Rows of cells in the atlas, 0 derives it from the atlas height
+	CharSize         [2]int // Width and height of each character cell (excluding padding)
+	CellPad          int    // Gutter between cells in the atlas, 0 for 1px; an atlas with no gutter sets Packed instead
+	Packed           bool   // Cells are packed edge to edge in the atlas with no gutter, whatever CellPad is
+	CharSet          string // String containing all supported characters in order matching atlas
+	CharWidths       []int  // Width of each character (indices match CharSet)
+	DefaultCharWidth int    // Width of characters past the end of CharWidths, 0 to leave them undrawn
+	Bearings         []int  // Optional x offset each character is drawn at relative to the cursor (indices match CharSet)
+	Advances         []int  // Optional distance the cursor moves past each character instead of CharWidths+LetterPad, so ink can overhang it (indices match CharSet)
+	NewlinePad       int    // Extra vertical padding between lines
+	LetterPad        int    // Extra horizontal padding between characters
+	WordSpacing      int    // Extra pixels each space advances, on top of LetterPad, negative for tighter word gaps
+	TabSpaces        int    // Spaces a tab is drawn, measured and wrapped as, 0 for 4
+
+	AdvanceOverride map[rune]int    // Advance used instead of CharWidths+LetterPad for specific runes
+	Combining       map[rune][2]int // Marks drawn over the previous glyph at this X/Y offset without advancing
+	Aliases         map[rune]rune   // Runes drawn, measured and wrapped exactly like another rune the font has
+	Ligatures       map[string]rune // Sequences of runes, like "fi" or "->", drawn as the glyph of a single rune
+	Shortcodes      map[string]rune // Names that text writes as :name: to draw the rune, with \: for a literal colon
+	DoubleWidth     map[rune]bool   // Characters, like kana, whose glyph spans two grid cells and that advance twice the cell width, plus LetterPad
+
+	// HangingPunctuation lets punctuation like quotes, commas and periods
+	// hang this many pixels of their advance into the margin when they start
+	// or end a line, so ragged edges look straight. Wrapping doesn't count
+	// the hanging part, and the layout grows to fit it, so it's never clipped,
+	// unless LayoutOptions.Width fixes its width, when it hangs inside the box.
+	HangingPunctuation map[rune]int
+
+	CollapseWhitespace bool // Treat runs of spaces and tabs as a single space when rendering, measuring and wrapping
+	CollapseNewlines   bool // Also collapse newlines into those runs, when CollapseWhitespace is set
+
+	ControlChars  ControlCharPolicy // How non-printing ASCII is drawn
+	Overstrike    bool              // Draw the rune after a '\b' on top of the one before it, like man pages do for bold and underline
+	SurfaceFormat uint32            // Pixel format (sdl.PIXELFORMAT_*) of rendered surfaces, 0 for ARGB8888
+
+	ContrastThreshold float64     // Background luminance above which RenderStringAutoContrast picks dark text over light, 0 for 0.179, where black and white contrast equally
+	CheckboxMarkers   [][2]string // Unchecked and checked markers RenderCheckbox chooses from, in order of preference, empty for ☐☑, then ✗✓, then [ ] [x]
+
+	Rects []GlyphSource // Optional source rect and advance of each character, used instead of the grid and CharWidths (indices match CharSet)
+
+	shaper        func(runes []rune) []ShapedGlyph // set by SetShaper
+	numberCache   map[numberKey]*numberCells       // glyphs pre-rendered by RenderNumber
+	smoothAtlases map[int]*sdl.Surface             // enlarged atlases by scale, generated by SmoothScales
+	indices       map[rune]int                     // index of each character of indicesFor, built by PrecomputeCaches
+	indicesFor    string                           // the CharSet indices was built for, so a changed charset isn't looked up in it
+	dpiBase       *Font                            // the font before SetDPIScale enlarged it, nil at scale 1
+	dpiScale      int                              // the scale SetDPIScale enlarged it by
+}
+
+// alias returns the rune char is drawn as, which is char itself unless it's in Aliases
+func (font *Font) alias(char rune) rune {
+	if target, ok := font.Aliases[char]; ok {
+		return target
+	}
+	return char
+}
+
+// glyphIndex returns the position of char in the charset, counted in runes
+// rather than bytes, or -1 if the font doesn't have it
+func (font *Font) glyphIndex(char rune) int {
+	char = font.alias(char)
+	if font.indicesFor == font.CharSet && font.indices != nil {
+		if index, ok := font.indices[char]; ok {
+			return index
+		}
+		return -1
+	}
+	index := 0
+	for _, c := range font.CharSet {
+		if c == char {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// PrecomputeCaches builds the table glyph lookups use instead of searching
+// the charset, so looking characters up doesn't slow down as the charset
+// grows. It's a no-op when the table is already built for the current
+// CharSet, and lookups go back to searching if CharSet changes afterwards.
+// Call it before rendering from several goroutines, since it writes to the
+// font.
+func (font *Font) PrecomputeCaches() {
+	if font.indicesFor == font.CharSet && font.indices != nil {
+		return
+	}
+	indices := make(map[rune]int, utf8.RuneCountInString(font.CharSet))
+	index := 0
+	for _, c := range font.CharSet {
+		if _, ok := indices[c]; !ok {
+			indices[c] = index
+		}
+		index++
+	}
+	font.indices, font.indicesFor = indices, font.CharSet
+}
+
+// bearing returns the x offset char is drawn at relative to the cursor
+func (font *Font) bearing(char rune) int {
+	if index := font.glyphIndex(char); index >= 0 && index < len(font.Bearings) {
+		return font.Bearings[index]
+	}
+	return 0
+}
+
+// charWidth returns the width of the glyph at index in a grid font, from
+// CharWidths or DefaultCharWidth, and false if it has neither
+func (font *Font) charWidth(index int) (int, bool) {
+	if index < len(font.CharWidths) {
+		return font.CharWidths[index], true
+	}
+	return font.DefaultCharWidth, font.DefaultCharWidth > 0
+}
+
+// cellPad returns the gutter between cells in the atlas
+func (font *Font) cellPad() int {
+	if font.Packed {
+		return 0
+	}
+	if font.CellPad <= 0 {
+		return 1
+	}
+	return font.CellPad
+}
+
+// gridHeight returns the number of rows of cells in the atlas
+func (font *Font) gridHeight() int {
+	if font.GridHeight > 0 {
+		return font.GridHeight
+	}
+	// the last row doesn't need padding below it, and may be cut short by the
+	// bottom of the atlas
+	pitch := font.CharSize[1] + font.cellPad()
+	return (int(font.Atlas.H) + pitch - 1) / pitch
+}
+
+// AtlasInfo returns the atlas's size in pixels, and the columns and rows of
+// grid cells the charset takes up in it. A charset that fits the atlas has
+// rows no greater than the atlas has room for.
+func (font *Font) AtlasInfo() (w, h, cols, rows int) {
+	cells := font.cell(utf8.RuneCountInString(font.CharSet))
+	return int(font.Atlas.W), int(font.Atlas.H), font.GridWidth, (cells + font.GridWidth - 1) / font.GridWidth
+}
+
+// Validate checks that the font's charset, metrics and atlas agree with each
+// other, reporting every glyph that couldn't be drawn, such as ones whose cell
+// falls outside the atlas. Those glyphs render as if the font didn't have them.
+func (font *Font) Validate() error {
+	count := utf8.RuneCountInString(font.CharSet)
+	var errs []error
+	if font.Atlas == nil {
+		return errors.New("font has no atlas")
+	}
+	if font.Rects != nil {
+		if len(font.Rects) != count {
+			errs = append(errs, fmt.Errorf("%d rects for %d characters", len(font.Rects), count))
+		}
+	} else {
+		if font.GridWidth <= 0 {
+			return fmt.Errorf("grid width %d isn't positive", font.GridWidth)
+		}
+		// DefaultCharWidth stands in for widths past the end of the table
+		if len(font.CharWidths) > count || len(font.CharWidths) < count && font.DefaultCharWidth <= 0 {
+			errs = append(errs, fmt.Errorf("%d widths for %d characters", len(font.CharWidths), count))
+		}
+	}
+	if len(font.Bearings) > count {
+		errs = append(errs, fmt.Errorf("%d bearings for %d characters", len(font.Bearings), count))
+	}
+	if len(font.Advances) > count {
+		errs = append(errs, fmt.Errorf("%d advances for %d characters", len(font.Advances), count))
+	}
+
+	errs = append(errs, font.aliasErrors()...)
+
+	seen := make(map[rune]bool, count)
+	for _, char := range font.CharSet {
+		if seen[char] {
+			errs = append(errs, fmt.Errorf("Character %q is in the charset more than once", char))
+			continue
+		}
+		seen[char] = true
+		if _, err := font.glyphRect(char); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// aliasErrors reports aliases that point at another alias, which isn't
+// followed, or at a character the font doesn't have
+func (font *Font) aliasErrors() (errs []error) {
+	for _, char := range slices.Sorted(maps.Keys(font.Aliases)) {
+		target := font.Aliases[char]
+		if next, chained := font.Aliases[target]; chained {
+			errs = append(errs, fmt.Errorf("alias %q -> %q is chained on to %q", char, target, next))
+		} else if font.glyphIndex(target) < 0 {
+			errs = append(errs, fmt.Errorf("alias %q -> %q targets a character not in the charset", char, target))
+		}
+	}
+	return errs
+}
+
+// glyphRect finds char's rect in the atlas, or says why it can't be drawn.
+// A rect with zero width is a legitimate glyph that draws nothing.
+func (font *Font) glyphRect(char rune) (sdl.Rect, error) {
+	index := font.glyphIndex(char)
 	if index < 0 {
-		log.Printf("Character %q not found in font charset", char)
-		return sdl.Rect{X: 0, Y: 0, W: 0, H: 0}
+		return sdl.Rect{}, fmt.Errorf("Character %q not found in font charset", char)
+	}
+
+	var rect sdl.Rect
+	inGrid := true
+	if font.Rects != nil {
+		if index >= len(font.Rects) {
+			return sdl.Rect{}, fmt.Errorf("Character %q has no rect in the font's rect table", char)
+		}
+		rect = font.Rects[index].Rect
+	} else {
+		width, ok := font.charWidth(index)
+		if !ok {
+			return sdl.Rect{}, fmt.Errorf("Character %q has no width in CharWidths", char)
+		}
+		cell := font.cell(index)
+		gridX, gridY := font.cellOrigin(cell)
+		rect = sdl.Rect{X: int32(gridX), Y: int32(gridY), W: int32(width), H: int32(font.CharSize[1])}
+		inGrid = cell/font.GridWidth < font.gridHeight()
+		// an atlas cropped to its ink can end part way through the last row
+		if inGrid && rect.Y < font.Atlas.H {
+			rect.H = min(rect.H, font.Atlas.H-rect.Y)
+		}
 	}
 
-	gridX := int32((index % font.GridWidth) * (font.CharSize[0] + 1)) // +1 for padding
-	gridY := int32((index / font.GridWidth) * (font.CharSize[1] + 1)) // +1 for padding
-	width := int32(font.CharWidths[index])
-	height := int32(font.CharSize[1])
+	// a charset that has drifted from the atlas would otherwise draw nothing, silently
+	if !inGrid || rect.X < 0 || rect.Y < 0 || rect.X+rect.W > font.Atlas.W || rect.Y+rect.H > font.Atlas.H {
+		return sdl.Rect{}, fmt.Errorf("Character %q glyph out of atlas bounds (%dx%d cell at %d,%d in a %dx%d atlas)", char, rect.W, rect.H, rect.X, rect.Y, font.Atlas.W, font.Atlas.H)
+	}
+
+	return rect, nil
+}
+
+// GlyphRect returns char's source rect in the atlas, and false if the font
+// can't draw it. A true result with a zero width rect is a glyph that
+// intentionally draws nothing but still advances the cursor.
+func (font *Font) GlyphRect(char rune) (sdl.Rect, bool) {
+	rect, err := font.glyphRect(char)
+	return rect, err == nil
+}
 
-	return sdl.Rect{X: gridX, Y: gridY, W: width, H: height}
+// gets the glyph rect from the atlas, logging glyphs that can't be drawn
+func (font *Font) loadGlyph(char rune) (sdl.Rect, bool) {
+	rect, err := font.glyphRect(char)
+	if err != nil {
+		log.Print(err)
+		return rect, false
+	}
+	return rect, true
 }
 
-// renderString draws text to the screen with specified position and color
+// RenderString draws text onto a new surface, starting a new line at each newline
 func (font *Font) RenderString(text string, r, g, b float64) *sdl.Surface { // 0-1 rgb color
-	cursorX := 0
-	cursorY := 0
+	return font.layout(text, LayoutOptions{}).Render(r, g, b)
+}
 
+// newSurface creates a blank 32-bit surface to render text onto
+func newSurface(width, height int) *sdl.Surface {
 	surface, err := sdl.CreateRGBSurface(
 		0,
-		int32(font.getStringLen(text)),
-		int32(font.CharSize[1]),
+		int32(width),
+		int32(height),
 		32,
 		0x00FF0000,
 		0x0000FF00,
@@ -52,50 +295,37 @@ func (font *Font) RenderString(text string, r, g, b float64) *sdl.Surface { // 0
 	if err != nil {
 		panic(err)
 	}
+	return surface
+}
 
-	// set modulation
-	font.Atlas.SetColorMod(uint8(r*255), uint8(g*255), uint8(b*255))
-
-	for _, char := range text {
-		if char == '\n' {
-			// Handle newlines
-			cursorX = 0
-			cursorY += font.CharSize[1] + font.NewlinePad
-			continue
-		}
-
-		srcRect := font.loadGlyph(char)
-		if srcRect.W == 0 || srcRect.H == 0 {
-			// skip unknown chars
-			continue
-		}
-
-		dstRect := sdl.Rect{X: int32(cursorX), Y: int32(cursorY), W: srcRect.W, H: srcRect.H}
-
-		// blit
-		font.Atlas.Blit(
-			&srcRect,
-			surface,
-			&dstRect,
-		)
-
-		// Advance cursor
-		charWidth := font.CharWidths[strings.IndexRune(font.CharSet, char)]
-		cursorX += charWidth + font.LetterPad
+// outputSurface creates a blank surface in the font's SurfaceFormat for
+// rendered text to be returned on
+func (font *Font) outputSurface(width, height int) *sdl.Surface {
+	if font.SurfaceFormat == 0 {
+		return newSurface(width, height)
+	}
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, int32(width), int32(height), 32, font.SurfaceFormat)
+	if err != nil {
+		panic(err)
 	}
 	return surface
 }
 
 func (font Font) getStringLen(text string) (ln int) {
-	for i := range text {
+	glyphs, _ := font.shape([]rune(font.normalize(font.substitute(text))), true)
+	for _, glyph := range glyphs {
 		// Advance cursor
-		charWidth := font.CharWidths[strings.IndexRune(font.CharSet, rune(text[i]))]
-		ln += charWidth + font.LetterPad
+		ln += font.shapedAdvance(glyph)
 	}
 	return
 }
 
-// newFont creates a new Font from an atlas image file
+// newFont creates a new Font from an atlas image file. Its cells are separated
+// by a 1px gutter; set Packed for atlases packed edge to edge. Gutters
+// only matter when the atlas is sampled with filtering, such as a scaled
+// texture on the GPU with linear filtering, which would otherwise blend in
+// pixels from neighbouring glyphs. Surface blits and nearest neighbour
+// scaling never read outside a glyph's cell.
 func NewFont(atlasPath string, gridWidth int, charSet string, charWidths []int) (font Font) {
 	// Load font atlas image
 	surface, err := img.Load(atlasPath)
@@ -103,17 +333,21 @@ func NewFont(atlasPath string, gridWidth int, charSet string, charWidths []int)
 		panic(err)
 	}
 
-	font = Font{
-		Atlas:      surface,
+	return fontFromAtlas(surface, gridWidth, charSet, charWidths)
+}
+
+// fontFromAtlas creates a Font with the default metrics from an already loaded atlas
+func fontFromAtlas(atlas *sdl.Surface, gridWidth int, charSet string, charWidths []int) Font {
+	return Font{
+		Atlas:      atlas,
 		GridWidth:  gridWidth,
 		CharSize:   [2]int{5, 11}, // Most chars are 5x7, some extend below baseline to 11px
+		CellPad:    1,
 		CharSet:    charSet,
 		CharWidths: charWidths,
 		LetterPad:  1,
 		NewlinePad: 5,
 	}
-
-	return
 }
 
 // Default font using the Isometrica typeface
@@ -121,7 +355,7 @@ func MakeDefaultFont() Font {
 	return NewFont(
 		"font/assets/font_atlas.png",
 		10, // Characters per row in atlas
-		" !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[]\\^_`abcdefghijklmnopqrstuvwxyz{}|~", // add support for ⟨⟩⟪⟫☺
+		" !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[]\\^_`abcdefghijklmnopqrstuvwxyz{}|~⟨⟩⟪⟫☺",
 		// Character widths (matching order of CharSet above):
 		[]int{
 			3,                            // Space
@@ -160,6 +394,11 @@ func MakeDefaultFont() Font {
 			3, // |
 			1, // }
 			4, // ~
+			2, // ⟨
+			2, // ⟩
+			4, // ⟪
+			4, // ⟫
+			5, // ☺
 		},
 	)
 }
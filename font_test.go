@@ -0,0 +1,422 @@
+package font
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// moduleDir is the directory holding the module, for finding its assets
+var moduleDir string
+
+// MakeDefaultFont loads its atlas from font/assets, so tests run from a
+// directory where font is the module
+func TestMain(m *testing.M) {
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	moduleDir = wd
+	dir, err := os.MkdirTemp("", "tiny-font")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Symlink(wd, filepath.Join(dir, "font")); err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// writePNG writes img to a PNG file at path
+func writePNG(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// rgba returns the color of every pixel of surface, row by row, whatever its format
+func rgba(surface *sdl.Surface) [][4]uint8 {
+	if surface.MustLock() {
+		surface.Lock()
+		defer surface.Unlock()
+	}
+	pixels := surface.Pixels()
+	out := make([][4]uint8, 0, surface.W*surface.H)
+	for y := 0; y < int(surface.H); y++ {
+		for x := 0; x < int(surface.W); x++ {
+			r, g, b, a := sdl.GetRGBA(getPixel(surface, pixels, x, y), surface.Format)
+			out = append(out, [4]uint8{r, g, b, a})
+		}
+	}
+	return out
+}
+
+// assertSamePixels fails the test unless a and b are the same size with the same pixels
+func assertSamePixels(t *testing.T, name string, a, b *sdl.Surface) {
+	t.Helper()
+	if a.W != b.W || a.H != b.H {
+		t.Errorf("%s: %dx%d, want %dx%d", name, a.W, a.H, b.W, b.H)
+		return
+	}
+	pa, pb := rgba(a), rgba(b)
+	for i := range pa {
+		if pa[i] != pb[i] {
+			t.Errorf("%s: pixel %d,%d is %v, want %v", name, i%int(a.W), i/int(a.W), pa[i], pb[i])
+			return
+		}
+	}
+}
+
+// inkColumns returns the first and last columns of surface with any ink, and
+// false if it has none
+func inkColumns(surface *sdl.Surface) (first, last int, ok bool) {
+	pixels := rgba(surface)
+	first, last = int(surface.W), -1
+	for i, p := range pixels {
+		if p[3] > 0 {
+			first, last = min(first, i%int(surface.W)), max(last, i%int(surface.W))
+		}
+	}
+	return first, last, last >= 0
+}
+
+func TestDefaultFontSpecialGlyphs(t *testing.T) {
+	font := MakeDefaultFont()
+	for _, test := range []struct {
+		char  string
+		width int
+	}{
+		{"⟨", 2}, {"⟩", 2}, {"⟪", 4}, {"⟫", 4}, {"☺", 5},
+	} {
+		surface := font.RenderString(test.char, 1, 1, 1)
+		first, last, ok := inkColumns(surface)
+		if !ok {
+			t.Errorf("RenderString(%q) drew nothing", test.char)
+			continue
+		}
+		if first != 0 || last+1 != test.width {
+			t.Errorf("RenderString(%q) has ink in columns %d to %d, want 0 to %d", test.char, first, last, test.width-1)
+		}
+		if w, _ := font.Measure(test.char); w != test.width+font.LetterPad {
+			t.Errorf("Measure(%q) = %d, want %d", test.char, w, test.width+font.LetterPad)
+		}
+	}
+}
+
+// cellColors are the colors packedFont fills each of its cells with
+var cellColors = [6][4]uint8{
+	{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255},
+	{255, 255, 0, 255}, {0, 255, 255, 255}, {255, 0, 255, 255},
+}
+
+// packedFont returns a font of the digits 0 to 5 in a 3x2 grid of 4x5 cells
+// packed edge to edge, each cell filled with its color from cellColors
+func packedFont() Font {
+	atlas := newSurface(12, 10)
+	for i, c := range cellColors {
+		cell := sdl.Rect{X: int32(i % 3 * 4), Y: int32(i / 3 * 5), W: 4, H: 5}
+		atlas.FillRect(&cell, sdl.MapRGBA(atlas.Format, c[0], c[1], c[2], c[3]))
+	}
+	return Font{
+		Atlas:      atlas,
+		GridWidth:  3,
+		CharSize:   [2]int{4, 5},
+		Packed:     true,
+		CharSet:    "012345",
+		CharWidths: []int{4, 4, 4, 4, 4, 4},
+		LetterPad:  1,
+	}
+}
+
+// near reports whether every channel of a is within 1 of b's
+func near(a, b [4]uint8) bool {
+	for i := range a {
+		if int(a[i])-int(b[i]) > 1 || int(b[i])-int(a[i]) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPackedAtlas(t *testing.T) {
+	font := packedFont()
+	for i, char := range font.CharSet {
+		rect, ok := font.GlyphRect(char)
+		want := sdl.Rect{X: int32(i % 3 * 4), Y: int32(i / 3 * 5), W: 4, H: 5}
+		if !ok || rect != want {
+			t.Errorf("GlyphRect(%q) = %v, %v, want %v", char, rect, ok, want)
+		}
+	}
+
+	// each glyph tinted at every scale must be its cell's color and nothing
+	// of its neighbours', with the LetterPad column between glyphs left empty
+	tint := [3]float64{1, 0.5, 1}
+	check := func(name string, surface *sdl.Surface, scale int) {
+		t.Helper()
+		pixels := rgba(surface)
+		for i, p := range pixels {
+			x := i % int(surface.W)
+			glyph, col := x/(5*scale), x%(5*scale)
+			want := [4]uint8{}
+			if col < 4*scale {
+				c := cellColors[glyph]
+				want = [4]uint8{c[0], uint8(int(c[1]) * 127 / 255), c[2], 255}
+			}
+			if !near(p, want) {
+				t.Errorf("%s: pixel %d,%d is %v, want %v", name, x, i/int(surface.W), p, want)
+				return
+			}
+		}
+	}
+	check("RenderString", font.RenderString(font.CharSet, tint[0], tint[1], tint[2]), 1)
+	for _, scale := range []int{1, 2, 3} {
+		surface, err := font.RenderNumber(font.CharSet, scale, tint[0], tint[1], tint[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(fmt.Sprintf("RenderNumber at scale %d", scale), surface, scale)
+	}
+}
+
+func TestCellPadDefaultsToGutter(t *testing.T) {
+	// fonts made before CellPad existed leave it 0 and keep their 1px gutters
+	font := Font{Atlas: newSurface(11, 11), GridWidth: 2, CharSize: [2]int{5, 5}, CharSet: "abcd", CharWidths: []int{5, 5, 5, 5}}
+	for i, want := range []sdl.Rect{{X: 0, Y: 0, W: 5, H: 5}, {X: 6, Y: 0, W: 5, H: 5}, {X: 0, Y: 6, W: 5, H: 5}, {X: 6, Y: 6, W: 5, H: 5}} {
+		char := rune(font.CharSet[i])
+		if rect, ok := font.GlyphRect(char); !ok || rect != want {
+			t.Errorf("GlyphRect(%q) = %v, %v, want %v", char, rect, ok, want)
+		}
+	}
+}
+
+func TestDoubleWidthAdvance(t *testing.T) {
+	font := Font{
+		Atlas:       newSurface(19, 5),
+		GridWidth:   4,
+		CharSize:    [2]int{4, 5},
+		CharSet:     "ab日",
+		CharWidths:  []int{4, 4, 9},
+		LetterPad:   1,
+		DoubleWidth: map[rune]bool{'日': true},
+	}
+	// a wide glyph spans two cells and is followed by one LetterPad, not two
+	for _, test := range []struct {
+		text  string
+		width int
+	}{
+		{"a", 5}, {"日", 9}, {"a日b", 19}, {"日日", 18}, {"ab日ab", 29},
+	} {
+		if w, _ := font.Measure(test.text); w != test.width {
+			t.Errorf("Measure(%q) = %d, want %d", test.text, w, test.width)
+		}
+		layout, err := font.Layout(test.text, LayoutOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w, _ := layout.Size(); w != test.width {
+			t.Errorf("Layout(%q) is %d wide, want %d", test.text, w, test.width)
+		}
+	}
+}
+
+func TestDefaultFontGlyphsInAtlas(t *testing.T) {
+	// the atlas ends 7px into its last row of 11px cells, which still holds
+	// glyphs, only cut short
+	font := MakeDefaultFont()
+	if font.Atlas.H != 115 {
+		t.Fatalf("the default atlas is %d tall, want 115", font.Atlas.H)
+	}
+	if err := font.Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, char := range font.CharSet {
+		if _, ok := font.GlyphRect(char); !ok {
+			t.Errorf("GlyphRect(%q) is outside the atlas", char)
+		}
+	}
+}
+
+func TestSurfaceFormat(t *testing.T) {
+	font := MakeDefaultFont()
+	want := font.RenderString("Hi!", 1, 0.5, 0.25)
+	for _, test := range []struct {
+		name   string
+		format uint32
+	}{
+		{"ARGB8888", sdl.PIXELFORMAT_ARGB8888},
+		{"RGBA8888", sdl.PIXELFORMAT_RGBA8888},
+		{"ABGR8888", sdl.PIXELFORMAT_ABGR8888},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			font := font
+			font.SurfaceFormat = test.format
+			surface := font.RenderString("Hi!", 1, 0.5, 0.25)
+			if surface.Format.Format != test.format {
+				t.Errorf("surface format is %s", sdl.GetPixelFormatName(uint(surface.Format.Format)))
+			}
+			assertSamePixels(t, "RenderString", surface, want)
+		})
+	}
+}
+
+func TestMultiByteCharSet(t *testing.T) {
+	// ☺ and é take 3 and 2 bytes, so byte offsets would put a past the grid
+	font := packedFont()
+	font.CharSet = "☺é0ab1"
+	for _, cached := range []bool{false, true} {
+		if cached {
+			font.PrecomputeCaches()
+		}
+		for i, char := range []rune(font.CharSet) {
+			if index := font.glyphIndex(char); index != i {
+				t.Errorf("cached %t: glyphIndex(%q) = %d, want %d", cached, char, index, i)
+			}
+			want := sdl.Rect{X: int32(i % 3 * 4), Y: int32(i / 3 * 5), W: 4, H: 5}
+			if rect, ok := font.GlyphRect(char); !ok || rect != want {
+				t.Errorf("cached %t: GlyphRect(%q) = %v, %v, want %v", cached, char, rect, ok, want)
+			}
+		}
+	}
+	if c := rgba(font.RenderString("a", 1, 1, 1))[0]; c != cellColors[3] {
+		t.Errorf("a is drawn %v, want its cell's color %v", c, cellColors[3])
+	}
+}
+
+func TestZeroWidthGlyph(t *testing.T) {
+	font := packedFont()
+	font.CharWidths[1] = 0 // 1 is a legitimately empty glyph, like a marker
+	if rect, ok := font.GlyphRect('1'); !ok || rect.W != 0 {
+		t.Errorf("GlyphRect('1') = %v, %v, want a zero wide rect", rect, ok)
+	}
+	if _, ok := font.GlyphRect('9'); ok {
+		t.Error("GlyphRect('9') found a glyph the font doesn't have")
+	}
+
+	// the empty glyph still advances by LetterPad, the missing one not at all
+	if w, _ := font.Measure("010"); w != 5+1+5 {
+		t.Errorf("Measure(\"010\") = %d, want %d", w, 5+1+5)
+	}
+	if w, _ := font.Measure("090"); w != 5+5 {
+		t.Errorf("Measure(\"090\") = %d, want %d", w, 5+5)
+	}
+	surface := font.RenderString("010", 1, 1, 1)
+	pixels := rgba(surface)
+	for x := 0; x < int(surface.W); x++ {
+		want := [4]uint8{}
+		if x < 4 || x >= 6 && x < 10 {
+			want = cellColors[0]
+		}
+		if p := pixels[x]; p != want {
+			t.Errorf("pixel %d,0 of \"010\" is %v, want %v", x, p, want)
+		}
+	}
+}
+
+func TestMeasuredAsRendered(t *testing.T) {
+	// the font has no kerning table; AdvanceOverride, WordSpacing and a
+	// shaper's advances are what adjust advances the way kern pairs would
+	font := MakeDefaultFont()
+	font.Ligatures = map[string]rune{"->": '☺', "<<": '⟪', ">>": '⟫'}
+	font.AdvanceOverride = map[rune]int{'T': 4, '☺': 7}
+	font.WordSpacing = -1
+	shaped := font
+	shaped.SetShaper(func(runes []rune) []ShapedGlyph {
+		glyphs := make([]ShapedGlyph, len(runes))
+		for i, char := range runes {
+			glyphs[i] = ShapedGlyph{Glyph: char, Cluster: i}
+			if char == 'o' {
+				glyphs[i].Advance, glyphs[i].HasAdvance = 3, true
+			}
+		}
+		return glyphs
+	})
+	for _, text := range []string{"->", "a -> b", "<<To>> the end", "Total -> 100", "->->"} {
+		for name, font := range map[string]*Font{"ligatures": &font, "shaper": &shaped} {
+			surface := font.RenderString(text, 1, 1, 1)
+			w, _ := font.Measure(text)
+			if ln := font.getStringLen(text); ln != int(surface.W) || w != int(surface.W) {
+				t.Errorf("%s: %q measures %d and %d, but renders %d wide", name, text, ln, w, surface.W)
+			}
+		}
+	}
+	if w, _ := font.Measure("->"); w != 7 {
+		t.Errorf("Measure(\"->\") = %d, want the ligature's advance of 7", w)
+	}
+}
+
+func TestLoadStripFont(t *testing.T) {
+	// glyphs 1, 3, 5 and 2 wide between magenta columns, with a doubled
+	// separator and ones at both ends
+	magenta := color.NRGBA{R: 255, B: 255, A: 255}
+	layout := "|a|bbb||ccccc|dd|"
+	strip := image.NewNRGBA(image.Rect(0, 0, len(layout), 4))
+	for x, c := range layout {
+		for y := 0; y < 4; y++ {
+			switch {
+			case c == '|':
+				strip.SetNRGBA(x, y, magenta)
+			case y > 0: // the top row is blank, so no glyph is all ink
+				strip.SetNRGBA(x, y, color.NRGBA{R: uint8(c), G: 10, B: 20, A: 255})
+			}
+		}
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "strip.png")
+	writePNG(t, path, strip)
+
+	sep := sdl.Color{R: 255, B: 255, A: 255}
+	font, err := LoadStripFont(path, "abcd", sep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []sdl.Rect{{X: 1, W: 1, H: 4}, {X: 3, W: 3, H: 4}, {X: 8, W: 5, H: 4}, {X: 14, W: 2, H: 4}} {
+		char := rune("abcd"[i])
+		if rect, ok := font.GlyphRect(char); !ok || rect != want {
+			t.Errorf("GlyphRect(%q) = %v, %v, want %v", char, rect, ok, want)
+		}
+		if w, _ := font.Measure(string(char)); w != int(want.W)+font.LetterPad {
+			t.Errorf("Measure(%q) = %d, want %d", char, w, int(want.W)+font.LetterPad)
+		}
+	}
+	surface := font.RenderString("dcba", 1, 1, 1)
+	pixels := rgba(surface)
+	x := 0
+	for _, char := range "dcba" {
+		rect, _ := font.GlyphRect(char)
+		for col := 0; col < int(rect.W); col++ {
+			if p := pixels[int(surface.W)+x+col]; p != [4]uint8{uint8(char), 10, 20, 255} {
+				t.Errorf("column %d of %q is %v", col, char, p)
+			}
+		}
+		x += int(rect.W) + font.LetterPad
+	}
+
+	if _, err := LoadStripFont(path, "abc", sep); err == nil {
+		t.Error("LoadStripFont succeeded with fewer characters than glyphs")
+	}
+}
+
+// assertColorMod fails the test if surface's color mod isn't r, g, b
+func assertColorMod(t *testing.T, name string, surface *sdl.Surface, r, g, b uint8) {
+	t.Helper()
+	if mr, mg, mb, err := surface.GetColorMod(); err != nil || mr != r || mg != g || mb != b {
+		t.Errorf("%s left the color mod at %d, %d, %d, want %d, %d, %d", name, mr, mg, mb, r, g, b)
+	}
+}
@@ -0,0 +1,48 @@
+package font
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// GradientSpan is what a horizontal gradient stretches across
+type GradientSpan int
+
+const (
+	GradientBlock GradientSpan = iota // the width of the whole block, so every line shares one gradient
+	GradientLine                      // each line's own width, so every line runs from start to end color
+)
+
+// RenderStringGradient draws text like RenderString in a color that fades
+// from r1, g1, b1 at the left to r2, g2, b2 at the right, across the block
+// or each line as span says. The text is rendered in white and each pixel
+// multiplied by the gradient's color at its x.
+func (font *Font) RenderStringGradient(text string, span GradientSpan, r1, g1, b1, r2, g2, b2 float64) *sdl.Surface {
+	layout := font.layout(text, LayoutOptions{})
+	surface := layout.Render(1, 1, 1)
+	if surface.MustLock() {
+		surface.Lock()
+		defer surface.Unlock()
+	}
+	pixels := surface.Pixels()
+
+	for y := 0; y < int(surface.H); y++ {
+		left, width := 0, layout.width
+		if span == GradientLine {
+			box := layout.LineRect(layout.lineAt(y))
+			left, width = int(box.X), int(box.W)
+		}
+		for x := 0; x < int(surface.W); x++ {
+			r, g, b, a := sdl.GetRGBA(getPixel(surface, pixels, x, y), surface.Format)
+			if a == 0 {
+				continue
+			}
+			t := 0.0
+			if width > 1 {
+				t = min(max(float64(x-left)/float64(width-1), 0), 1)
+			}
+			mix := func(c uint8, from, to float64) uint8 {
+				return uint8(float64(c) * (from + (to-from)*t))
+			}
+			setPixel(surface, pixels, x, y, sdl.MapRGBA(surface.Format, mix(r, r1, r2), mix(g, g1, g2), mix(b, b1, b2), a))
+		}
+	}
+	return surface
+}
@@ -0,0 +1,69 @@
+package font
+
+import "unicode"
+
+// continuesCluster reports whether rune i of runes belongs to the same
+// grapheme cluster as the rune before it, so carets never go between them
+// and words are never split there. A cluster is a rune followed by
+//   - marks drawn over it, which are runes in Combining and Unicode's
+//     nonspacing and enclosing marks (Mn and Me)
+//   - variation selectors, U+FE00 to U+FE0F
+//   - a zero width joiner, U+200D, and the rune after it, along with that
+//     rune's own marks and joiners, as in emoji ZWJ sequences
+//   - the rest of a ligature it starts
+//
+// and "\r\n" is a single cluster. Nothing else is treated specially, so
+// Hangul syllables spelled out as jamo and regional indicator pairs are
+// still a cluster per rune.
+func (font *Font) continuesCluster(runes []rune, glyphs []ShapedGlyph, i int) bool {
+	if i <= 0 || i >= len(runes) {
+		return false
+	}
+	return glyphs[i].Glyph == ligatureTail || font.isCombining(runes[i]) || joinsPrevious(runes[i-1], runes[i])
+}
+
+// joinsPrevious is the part of continuesCluster that doesn't depend on the
+// font: whether char continues the cluster prev is in by Unicode alone
+func joinsPrevious(prev, char rune) bool {
+	return unicode.In(char, unicode.Mn, unicode.Me) ||
+		(char >= 0xFE00 && char <= 0xFE0F) ||
+		char == '\u200d' || prev == '\u200d' ||
+		(prev == '\r' && char == '\n')
+}
+
+// NextCluster returns where a caret before index moves to when it moves
+// right: past the rest of the grapheme cluster after it, so a letter and its
+// accents are stepped over together. At the end of the text it stays put.
+func (layout *Layout) NextCluster(index int) int {
+	i := layout.laidOutIndex(index)
+	if layout.SourceIndex(i) > index {
+		return layout.SourceIndex(i) // index was inside a rune that was expanded or collapsed
+	}
+	text := layout.wrap.Text
+	for i++; i < len(text) && layout.font.continuesCluster(text, layout.wrap.glyphs, i); i++ {
+	}
+	return layout.SourceIndex(min(i, len(text)))
+}
+
+// PrevCluster is NextCluster for moving left. At the start of the text it
+// stays put.
+func (layout *Layout) PrevCluster(index int) int {
+	i := layout.laidOutIndex(index)
+	if layout.SourceIndex(i) > index && i > 0 {
+		i-- // index was inside the rune before i
+	}
+	if i == 0 {
+		return 0
+	}
+	text := layout.wrap.Text
+	for i--; i > 0 && layout.font.continuesCluster(text, layout.wrap.glyphs, i); i-- {
+	}
+	return layout.SourceIndex(i)
+}
+
+// IndexAtPoint returns the caret position closest to x, y relative to the
+// text origin, for placing the caret where the text was clicked. Like
+// IndexAbove it never lands inside a grapheme cluster.
+func (layout *Layout) IndexAtPoint(x, y int) int {
+	return layout.indexAt(layout.lineAt(y), x)
+}
@@ -0,0 +1,38 @@
+package font
+
+import (
+	"math/rand/v2"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// RenderStringJitter draws text like RenderString with each glyph nudged by
+// up to maxOffset pixels in x and y, for a hand drawn look. Offsets are random
+// but depend only on seed and each glyph's rune index, so the same text and
+// seed always render the same. The surface has maxOffset pixels of padding
+// on every side, with the text origin at maxOffset, maxOffset.
+func (font *Font) RenderStringJitter(text string, maxOffset int, seed int64, r, g, b float64) *sdl.Surface {
+	return font.layout(text, LayoutOptions{}).RenderJitter(maxOffset, seed, r, g, b)
+}
+
+// RenderJitter is RenderStringJitter for text that has already been laid
+// out, on a surface no wider than opts.MaxSurfaceWidth
+func (layout *Layout) RenderJitter(maxOffset int, seed int64, r, g, b float64) *sdl.Surface {
+	maxOffset = max(maxOffset, 0)
+	surface := layout.font.outputSurface(layout.capWidth(0, layout.width+2*maxOffset), layout.height+2*maxOffset)
+
+	atlas := layout.font.Atlas
+	if atlas.MustLock() {
+		atlas.Lock()
+		defer atlas.Unlock()
+	}
+	tint := sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+	layout.Walk(func(glyph GlyphPlacement) bool {
+		rng := rand.New(rand.NewPCG(uint64(seed), uint64(glyph.Index)))
+		dx := rng.IntN(2*maxOffset+1) - maxOffset
+		dy := rng.IntN(2*maxOffset+1) - maxOffset
+		compositeGlyph(atlas, glyph.Src, surface, maxOffset+int(glyph.Dst.X)+dx, maxOffset+int(glyph.Dst.Y)+dy, tint)
+		return true
+	})
+	return surface
+}
@@ -0,0 +1,523 @@
+package font
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Alignment controls where each line sits horizontally within a block
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// LayoutOptions controls how multi-line text is laid out
+type LayoutOptions struct {
+	MaxWidth int // wrap lines at this many pixels, 0 only breaks at newlines
+	Align    Alignment
+	Width    int // align against a box this wide instead of the widest line, 0 uses the widest line
+	MinWidth int // without Width, make the block at least this wide, so short text still gets full width backgrounds
+	TopPad   int // transparent pixels above the first line, moving every glyph down, for lining the text up with other elements
+
+	// MaxSurfaceWidth caps the block's width, and so the width of surfaces
+	// it's drawn onto, at this many pixels, cutting off glyphs that reach
+	// past it partway through if need be, for tickers that scroll the text
+	// themselves. Drawing into a surface clips this many pixels right of the
+	// text origin too. It doesn't wrap or move anything. 0 is no cap.
+	MaxSurfaceWidth int
+
+	// FirstLineIndent indents the first line of each paragraph by this many
+	// pixels, leaving it that much less room to wrap in. A negative indent is
+	// a hanging indent: every line of a paragraph but its first is indented.
+	FirstLineIndent int
+
+	// CollapseWhitespace lays runs of spaces and tabs out as a single space,
+	// for prose with irregular spacing. Unlike the font's CollapseWhitespace,
+	// indices the layout reports (glyph indices and selections) still refer
+	// to the text as it was passed in. TrimLines also drops the spaces at the
+	// start and end of each line of the text.
+	CollapseWhitespace bool
+	TrimLines          bool
+
+	// BalanceLines wraps paragraphs that take at most this many lines with
+	// lines as even in width as possible rather than each as full as
+	// possible, so a dialogue box doesn't end on a lone word. 0 only fills
+	// lines greedily. Lines still never break anywhere greedy wrapping
+	// wouldn't, or get wider than MaxWidth.
+	BalanceLines int
+
+	// NoLigatures draws every rune by itself even when the font has a
+	// ligature for it, for text fields that need a caret between each one
+	NoLigatures bool
+
+	// LineBackgrounds fills the block's full width behind each line with these
+	// colors in turn, cycling when there are more lines than colors. The fill
+	// replaces what's beneath it rather than blending.
+	LineBackgrounds []sdl.Color
+}
+
+// GlyphPlacement is a glyph positioned relative to the text origin
+type GlyphPlacement struct {
+	Char  rune
+	Index int      // rune index in the text passed in, -1 for an inserted hyphen
+	Src   sdl.Rect // source rect in the atlas
+	Dst   sdl.Rect // destination rect relative to the text origin
+}
+
+// Layout is text that has been wrapped, aligned and positioned once so it can
+// be rendered and queried many times. It isn't modified after it's created.
+type Layout struct {
+	font          *Font
+	opts          LayoutOptions
+	wrap          *WrapResult
+	glyphs        []GlyphPlacement
+	cells         []sdl.Rect // space taken up by each rune of the text, zero wide for runes consumed by a break
+	source        []int      // index in the text passed in of each rune of the laid out text and its end, nil when they're the same
+	sourceEnds    []int      // index in the text passed in where each rune of the laid out text ends, nil when each is a single rune
+	original      []rune     // the text passed in, when it differs from the laid out text
+	lineX         []int      // x offset of each line after alignment
+	width, height int
+}
+
+// LineHeight is the distance between the tops of two consecutive lines
+func (font *Font) LineHeight() int {
+	return font.CharSize[1] + font.NewlinePad
+}
+
+// Layout wraps and aligns text, positioning every glyph that will be drawn.
+// Text with N newlines has N+1 lines, so "a\n\nb" has a blank line between a
+// and b and a trailing newline ends the text with an empty line, which adds
+// to the height but not the width. Every line is LineHeight tall, blank or not.
+func (font *Font) Layout(text string, opts LayoutOptions) (*Layout, error) {
+	if opts.MaxWidth < 0 || opts.Width < 0 || opts.MinWidth < 0 || opts.MaxSurfaceWidth < 0 {
+		return nil, fmt.Errorf("negative layout width in %+v", opts)
+	}
+	if opts.TopPad < 0 {
+		return nil, fmt.Errorf("negative top pad %d", opts.TopPad)
+	}
+	if opts.Align < AlignLeft || opts.Align > AlignRight {
+		return nil, fmt.Errorf("unknown alignment %d", opts.Align)
+	}
+	layout := font.layout(text, opts)
+	if err := layout.wrap.shapeErr; err != nil {
+		return nil, fmt.Errorf("laying out %q: %w", text, err)
+	}
+	return layout, nil
+}
+
+// layout does the work of Layout for callers that have already checked opts
+func (font *Font) layout(text string, opts LayoutOptions) *Layout {
+	var source, sourceEnds []int
+	var original []rune
+	if len(font.Shortcodes) > 0 {
+		original = []rune(text)
+		var expanded []rune
+		expanded, source = font.expandShortcodes(original)
+		sourceEnds = source[1:]
+		text = string(expanded)
+	}
+	// the font's own collapsing is mapped here too, so indices still refer
+	// to the text passed in
+	if opts.CollapseWhitespace || font.CollapseWhitespace {
+		if original == nil {
+			original = []rune(text)
+			text, source = font.collapseSource(original, opts.TrimLines)
+		} else {
+			// map through both the substitution and the collapsing
+			var kept []int
+			text, kept = font.collapseSource([]rune(text), opts.TrimLines)
+			collapsedSource, collapsedEnd := make([]int, len(kept)), make([]int, len(kept)-1)
+			for i, index := range kept {
+				collapsedSource[i] = source[index]
+				if i < len(collapsedEnd) {
+					collapsedEnd[i] = sourceEnds[index]
+				}
+			}
+			source, sourceEnds = collapsedSource, collapsedEnd
+		}
+	}
+
+	wrap := font.wrap(text, opts.MaxWidth, opts.FirstLineIndent, opts.BalanceLines, !opts.NoLigatures)
+	lines := len(wrap.Lines)
+	layout := &Layout{
+		font:       font,
+		opts:       opts,
+		wrap:       wrap,
+		cells:      make([]sdl.Rect, len(wrap.Text)),
+		source:     source,
+		sourceEnds: sourceEnds,
+		original:   original,
+		lineX:      make([]int, lines),
+		height:     max(opts.TopPad, 0) + lines*font.CharSize[1] + (lines-1)*font.NewlinePad,
+	}
+
+	// how far the first and last runes of each line hang into the margins
+	hangs := make([][2]int, lines)
+	for i, line := range wrap.Lines {
+		if line.End > line.Start {
+			hangs[i] = [2]int{font.hang(wrap.Text, wrap.glyphs, line.Start), font.hang(wrap.Text, wrap.glyphs, line.End-1)}
+		}
+	}
+
+	layout.width = opts.Width
+	if layout.width <= 0 {
+		layout.width = opts.MinWidth
+		for i, line := range wrap.Lines {
+			layout.width = max(layout.width, wrap.indent(i)+line.Width-hangs[i][0]-hangs[i][1])
+		}
+	}
+
+	margin := 0 // room for glyphs hanging left of the block
+	for i, line := range wrap.Lines {
+		// an indent aligns as part of its line, and hanging runes don't
+		indent, width := wrap.indent(i), line.Width-hangs[i][0]-hangs[i][1]
+		switch opts.Align {
+		case AlignCenter:
+			layout.lineX[i] = max((layout.width-indent-width)/2, 0)
+		case AlignRight:
+			layout.lineX[i] = max(layout.width-indent-width, 0)
+		}
+		layout.lineX[i] += indent - hangs[i][0]
+		margin = max(margin, -layout.lineX[i])
+	}
+	switch {
+	case opts.Width > 0:
+		// the block is as wide as asked, so glyphs hang inside its edges
+		for i := range layout.lineX {
+			layout.lineX[i] = max(layout.lineX[i], 0)
+		}
+	case margin > 0:
+		for i := range layout.lineX {
+			layout.lineX[i] += margin
+		}
+		layout.width += margin
+	}
+	for i, line := range wrap.Lines {
+		if hangs[i][1] > 0 && opts.Width <= 0 {
+			layout.width = max(layout.width, layout.lineX[i]+line.Width) // room for glyphs hanging right of it
+		}
+	}
+
+	for i, line := range wrap.Lines {
+		cursorX := layout.lineX[i]
+		cursorY := layout.lineY(i)
+		base := sdl.Rect{X: int32(cursorX), Y: int32(cursorY)} // glyph that combining marks go over
+		place := func(glyph ShapedGlyph, index int) {
+			char := glyph.Glyph
+			if char == ligatureTail {
+				// every rune of a ligature shares its cell, so a caret inside
+				// one lands before it and selections take all of it or none
+				layout.cells[index] = layout.cells[index-1]
+				return
+			}
+			adv := font.shapedAdvance(glyph)
+			if index >= 0 {
+				layout.cells[index] = sdl.Rect{X: int32(cursorX), Y: int32(cursorY), W: int32(adv), H: int32(font.CharSize[1])}
+			}
+			if char, visible := font.displayRune(char); visible && char != '\t' {
+				if src, ok := font.loadGlyph(char); ok {
+					// bearings may tuck a glyph left of the cursor, but never off the surface
+					dst := sdl.Rect{X: int32(max(cursorX+font.bearing(char), 0)), Y: int32(cursorY + font.glyphTop(char)), W: src.W, H: src.H}
+					if offset, ok := font.Combining[char]; ok {
+						dst.X = base.X + int32(offset[0])
+						dst.Y = int32(cursorY + offset[1] + font.glyphTop(char))
+					} else {
+						base = dst
+					}
+					dst.X += int32(glyph.Offset[0])
+					dst.Y += int32(glyph.Offset[1])
+					glyphIndex := index
+					if index >= 0 {
+						glyphIndex = layout.SourceIndex(index)
+					}
+					layout.glyphs = append(layout.glyphs, GlyphPlacement{Char: char, Index: glyphIndex, Src: src, Dst: dst})
+				}
+			}
+			cursorX += adv
+		}
+
+		for index := line.Start; index < line.End; index++ {
+			place(wrap.glyphs[index], index)
+		}
+		for index := line.End; index < wrap.next(i); index++ {
+			layout.cells[index] = sdl.Rect{X: int32(cursorX), Y: int32(cursorY), H: int32(font.CharSize[1])}
+		}
+		if line.Break == BreakHyphen {
+			place(ShapedGlyph{Glyph: '-'}, -1)
+		}
+	}
+
+	if opts.Width <= 0 {
+		// make room for ink that overhangs the last advance
+		for _, glyph := range layout.glyphs {
+			layout.width = max(layout.width, int(glyph.Dst.X+glyph.Dst.W))
+		}
+	}
+	layout.width = layout.capWidth(0, layout.width)
+
+	return layout
+}
+
+// capWidth returns width, capped so that a surface that wide x pixels right
+// of the text origin ends at opts.MaxSurfaceWidth
+func (layout *Layout) capWidth(x, width int) int {
+	if layout.opts.MaxSurfaceWidth <= 0 {
+		return width
+	}
+	return max(min(width, layout.opts.MaxSurfaceWidth-x), 0)
+}
+
+// clipToCap narrows dst's clip rect to end opts.MaxSurfaceWidth pixels right
+// of the text origin at x, returning a func that puts it back
+func (layout *Layout) clipToCap(dst *sdl.Surface, x int) (restore func()) {
+	if layout.opts.MaxSurfaceWidth <= 0 {
+		return func() {}
+	}
+	clip := dst.ClipRect
+	capped := clip
+	capped.W = max(min(capped.W, int32(x+layout.opts.MaxSurfaceWidth)-capped.X), 0)
+	dst.SetClipRect(&capped)
+	return func() { dst.SetClipRect(&clip) }
+}
+
+// lineY returns the y of the top of a line, relative to the text origin
+func (layout *Layout) lineY(line int) int {
+	return max(layout.opts.TopPad, 0) + line*layout.font.LineHeight()
+}
+
+// lineAt returns the line at y, relative to the text origin, clamped to the
+// first and last lines
+func (layout *Layout) lineAt(y int) int {
+	return min(max((y-max(layout.opts.TopPad, 0))/layout.font.LineHeight(), 0), len(layout.wrap.Lines)-1)
+}
+
+// Size returns the width and height of the laid out block
+func (layout *Layout) Size() (w, h int) {
+	return layout.width, layout.height
+}
+
+// Text returns the text that was laid out
+func (layout *Layout) Text() string {
+	return string(layout.wrap.Text)
+}
+
+// SourceIndex maps a rune index into Text to the index of the same rune in
+// the text that was laid out, which differ when collapsing whitespace drops
+// runes or shortcodes are substituted. An index at the end of Text maps to
+// the end of the text.
+func (layout *Layout) SourceIndex(index int) int {
+	if layout.source == nil {
+		return index
+	}
+	return layout.source[min(max(index, 0), len(layout.source)-1)]
+}
+
+// sourceEnd returns the index in the text passed in just past the rune at
+// index in Text, which is more than one past its SourceIndex for a shortcode
+func (layout *Layout) sourceEnd(index int) int {
+	if layout.sourceEnds == nil || index < 0 || index >= len(layout.sourceEnds) {
+		return layout.SourceIndex(index) + 1
+	}
+	return layout.sourceEnds[index]
+}
+
+// Lines returns the wrapped lines of the layout
+func (layout *Layout) Lines() []WrappedLine {
+	return slices.Clone(layout.wrap.Lines)
+}
+
+// LineRect returns the box taken up by a line, relative to the text origin
+func (layout *Layout) LineRect(line int) sdl.Rect {
+	font := layout.font
+	return sdl.Rect{
+		X: int32(layout.lineX[line]),
+		Y: int32(layout.lineY(line)),
+		W: int32(layout.wrap.Lines[line].Width),
+		H: int32(font.CharSize[1]),
+	}
+}
+
+// Glyphs returns every glyph that will be drawn, in text order
+func (layout *Layout) Glyphs() []GlyphPlacement {
+	return slices.Clone(layout.glyphs)
+}
+
+// Walk calls fn with every glyph the layout draws, in text order, until fn
+// returns false. Rendering goes through Walk, so custom renderers that draw
+// each Src rect at Dst match the package's own output exactly. Walk is an
+// iterator, so it can also be ranged over:
+//
+//	for glyph := range layout.Walk {
+//		vertices = appendQuad(vertices, glyph.Src, glyph.Dst)
+//	}
+func (layout *Layout) Walk(fn func(glyph GlyphPlacement) bool) {
+	for _, glyph := range layout.glyphs {
+		if !fn(glyph) {
+			return
+		}
+	}
+}
+
+// Walk lays out text and calls fn with each glyph like Layout.Walk
+func (font *Font) Walk(text string, opts LayoutOptions, fn func(glyph GlyphPlacement) bool) {
+	font.layout(text, opts).Walk(fn)
+}
+
+// Render draws the layout onto a new surface the size of the block. It
+// composites rather than blits, so the atlas is left untouched and any number
+// of goroutines can render with the same font at once.
+func (layout *Layout) Render(r, g, b float64) *sdl.Surface {
+	surface := layout.font.outputSurface(layout.width, layout.height)
+	layout.CompositeInto(surface, 0, 0, r, g, b)
+	return surface
+}
+
+// RenderInto draws the layout onto dst with the text origin at x, y. It blits
+// through the atlas's color mod, so it's faster than CompositeInto but
+// mustn't be used on the same font from more than one goroutine at once.
+func (layout *Layout) RenderInto(dst *sdl.Surface, x, y int, r, g, b float64) {
+	defer layout.clipToCap(dst, x)()
+	layout.fillBackgrounds(dst, x, y)
+
+	atlas := layout.font.Atlas
+	atlas.SetColorMod(uint8(r*255), uint8(g*255), uint8(b*255))
+	clip := dst.ClipRect
+	layout.Walk(func(glyph GlyphPlacement) bool {
+		dstRect := glyph.Dst
+		dstRect.X += int32(x)
+		dstRect.Y += int32(y)
+		if !dstRect.HasIntersection(&clip) {
+			return true // clipped away entirely
+		}
+		atlas.Blit(&glyph.Src, dst, &dstRect)
+		return true
+	})
+}
+
+// RenderWrappedInto word wraps text at maxWidth and draws it straight onto
+// dst with the text origin at x, y, without an intermediate surface. Glyphs
+// outside dst's clip rect are skipped, so drawing a long paragraph that's
+// mostly scrolled out of view stays cheap.
+func (font *Font) RenderWrappedInto(dst *sdl.Surface, x, y, maxWidth int, text string, r, g, b float64) {
+	font.layout(text, LayoutOptions{MaxWidth: max(maxWidth, 0)}).RenderInto(dst, x, y, r, g, b)
+}
+
+// RenderStringInto draws text like RenderString straight onto dst with the
+// text origin at x, y, and returns where the cursor ends up, so the next piece
+// of a line can be drawn right after it, even in another color or font:
+//
+//	x, y = f.RenderStringInto(dst, x, y, "HP: ", 1, 1, 1)
+//	x, y = f.RenderStringInto(dst, x, y, "37", 1, 0, 0)
+//
+// The cursor is past the last glyph's LetterPad, just as if both pieces were
+// one string. Lines after a newline start at x, like they do in a block.
+func (font *Font) RenderStringInto(dst *sdl.Surface, x, y int, text string, r, g, b float64) (int, int) {
+	layout := font.layout(text, LayoutOptions{})
+	layout.RenderInto(dst, x, y, r, g, b)
+	endX, endY, _ := layout.EndCursor()
+	return x + endX, y + endY
+}
+
+// EndCursor returns where the cursor is after the last rune of the layout,
+// relative to the text origin: past the LetterPad of the last glyph, at the
+// top of the last line, and which line that is
+func (layout *Layout) EndCursor() (x, y, line int) {
+	line = len(layout.wrap.Lines) - 1
+	return layout.lineX[line] + layout.wrap.Lines[line].Width, layout.lineY(line), line
+}
+
+// fillBackgrounds fills the stripe behind each line with opts.LineBackgrounds.
+// Stripes reach down to the next line so they meet without gaps.
+func (layout *Layout) fillBackgrounds(dst *sdl.Surface, x, y int) {
+	colors := layout.opts.LineBackgrounds
+	if len(colors) == 0 {
+		return
+	}
+	defer layout.clipToCap(dst, x)()
+	font := layout.font
+	for i := range layout.wrap.Lines {
+		stripe := sdl.Rect{
+			X: int32(x),
+			Y: int32(y + layout.lineY(i)),
+			W: int32(layout.width),
+			H: int32(font.LineHeight()),
+		}
+		if i == len(layout.wrap.Lines)-1 {
+			stripe.H = int32(font.CharSize[1])
+		}
+		color := colors[i%len(colors)]
+		dst.FillRect(&stripe, sdl.MapRGBA(dst.Format, color.R, color.G, color.B, color.A))
+	}
+}
+
+// RenderStringAligned draws multi-line text with each line aligned within the
+// block. Without opts.Width lines align against the widest one; with it they
+// align against a box of that width, which is also the width of the surface.
+func (font *Font) RenderStringAligned(text string, opts LayoutOptions, r, g, b float64) *sdl.Surface {
+	return font.layout(text, opts).Render(r, g, b)
+}
+
+// RenderWithPlacements renders text like RenderString and also returns where
+// each of its glyphs was drawn, so callers can map between text and pixels
+func (font *Font) RenderWithPlacements(text string, r, g, b float64) (*sdl.Surface, []GlyphPlacement) {
+	layout := font.layout(text, LayoutOptions{})
+	return layout.Render(r, g, b), layout.Glyphs()
+}
+
+// RenderLines draws each of lines as one line of a block, aligned across the
+// widest, like RenderStringAligned would draw them joined with newlines. A
+// newline inside a line is drawn as a space rather than starting another.
+func (font *Font) RenderLines(lines []string, align Alignment, r, g, b float64) *sdl.Surface {
+	flat := make([]string, len(lines))
+	for i, line := range lines {
+		flat[i] = strings.ReplaceAll(line, "\n", " ")
+	}
+	return font.layout(strings.Join(flat, "\n"), LayoutOptions{Align: align}).Render(r, g, b)
+}
+
+// RenderLineSurfaces lays text out like Layout but draws each line onto a
+// surface of its own, as wide as the line and CharSize[1] tall, for callers
+// that scroll, cache or fade lines separately. Stacking the surfaces spacing
+// pixels apart reproduces the block, apart from alignment, which has no
+// effect on lines drawn by themselves, and LineBackgrounds, which aren't
+// drawn. An empty line gives a transparent surface 1 pixel wide, so the
+// surfaces always line up with the lines.
+func (font *Font) RenderLineSurfaces(text string, opts LayoutOptions, r, g, b float64) (lines []*sdl.Surface, spacing int, err error) {
+	layout, err := font.Layout(text, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// which line each glyph is on
+	lineOf := func(glyph GlyphPlacement) int {
+		if glyph.Index < 0 {
+			return layout.lineAt(int(glyph.Dst.Y)) // an inserted hyphen
+		}
+		return layout.caretLine(layout.laidOutIndex(glyph.Index))
+	}
+
+	widths := make([]int, len(layout.wrap.Lines))
+	for i := range layout.wrap.Lines {
+		widths[i] = max(layout.wrap.Lines[i].Width, 1)
+	}
+	for _, glyph := range layout.glyphs {
+		line := lineOf(glyph)
+		widths[line] = max(widths[line], int(glyph.Dst.X+glyph.Dst.W)-layout.lineX[line])
+	}
+
+	tint := sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+	for i := range layout.wrap.Lines {
+		surface := font.outputSurface(max(layout.capWidth(layout.lineX[i], widths[i]), 1), font.CharSize[1])
+		layout.compositeGlyphs(surface, -layout.lineX[i], -layout.lineY(i), tint, func(glyph GlyphPlacement) bool {
+			return lineOf(glyph) == i
+		})
+		lines = append(lines, surface)
+	}
+	return lines, font.LineHeight(), nil
+}
@@ -0,0 +1,365 @@
+package font
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestCollapseWhitespaceIndices(t *testing.T) {
+	const messy = "a   b\t\tc  \nd"
+	want := map[rune]int{'a': 0, 'b': 4, 'c': 7, 'd': 11}
+	for _, test := range []struct {
+		name     string
+		font     bool
+		opts     bool
+		collapse bool
+	}{
+		{"off", false, false, false},
+		{"layout option", false, true, true},
+		{"font option", true, false, true},
+		{"both", true, true, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			font := MakeDefaultFont()
+			font.CollapseWhitespace = test.font
+			layout, err := font.Layout(messy, LayoutOptions{CollapseWhitespace: test.opts})
+			if err != nil {
+				t.Fatal(err)
+			}
+			space, _ := font.advance(' ')
+			for _, glyph := range layout.Glyphs() {
+				if index, ok := want[glyph.Char]; ok && glyph.Index != index {
+					t.Errorf("%q has index %d, want %d", glyph.Char, glyph.Index, index)
+				}
+			}
+			bX, cX := layout.CaretX(4), layout.CaretX(7)
+			aAdv, _ := font.advance('a')
+			wantB := aAdv + 3*space
+			if test.collapse {
+				wantB = aAdv + space
+			}
+			if bX != wantB {
+				t.Errorf("caret before b is at %d, want %d", bX, wantB)
+			}
+			if bAdv, _ := font.advance('b'); test.collapse && cX != bX+bAdv+space {
+				t.Errorf("caret before c is at %d, want %d", cX, bX+bAdv+space)
+			}
+			if start := layout.LineStart(11); start != 11 {
+				t.Errorf("LineStart of d is %d, want 11", start)
+			}
+			if at := layout.IndexAtPoint(cX+1, 1); at != 7 {
+				t.Errorf("IndexAtPoint over c is %d, want 7", at)
+			}
+		})
+	}
+}
+
+func TestMaxSurfaceWidth(t *testing.T) {
+	const capped = 20
+	font := MakeDefaultFont()
+	layout, err := font.Layout("Hello, World!\nsecond line", LayoutOptions{
+		MaxSurfaceWidth: capped,
+		LineBackgrounds: []sdl.Color{{R: 40, A: 255}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	surfaces := map[string]*sdl.Surface{
+		"Render":          layout.Render(1, 1, 1),
+		"RenderWithCaret": layout.RenderWithCaret(13, 2, true, 1, 1, 1),
+		"RenderEffects": layout.RenderEffects([]Effect{
+			{Offset: [2]int{3, 3}, Color: sdl.Color{A: 128}},
+			{Thickness: 2, Color: sdl.Color{B: 128, A: 255}},
+			{Color: sdl.Color{R: 255, G: 255, B: 255, A: 255}},
+		}),
+		"RenderJitter": layout.RenderJitter(3, 1, 1, 1, 1),
+		"RenderRange":  layout.RenderRange(0, 25, 1, 1, 1),
+		"RenderBlink":  layout.RenderBlink(nil, true, 1, 1, 1),
+	}
+	lines, _, err := font.RenderLineSurfaces("Hello, World!\nsecond line", LayoutOptions{MaxSurfaceWidth: capped}, 1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, line := range lines {
+		surfaces[fmt.Sprintf("RenderLineSurfaces line %d", i)] = line
+	}
+	for name, surface := range surfaces {
+		if surface.W > capped {
+			t.Errorf("%s is %d wide, past the cap of %d", name, surface.W, capped)
+		}
+	}
+
+	const x = 10
+	into := map[string]func(dst *sdl.Surface){
+		"RenderInto":    func(dst *sdl.Surface) { layout.RenderInto(dst, x, 5, 1, 1, 1) },
+		"CompositeInto": func(dst *sdl.Surface) { layout.CompositeInto(dst, x, 5, 1, 1, 1) },
+	}
+	for name, draw := range into {
+		dst := newSurface(200, 40)
+		draw(dst)
+		first, last, ok := inkColumns(dst)
+		if !ok || first != x || last != x+capped-1 {
+			t.Errorf("%s wrote columns %d to %d, want %d to %d", name, first, last, x, x+capped-1)
+		}
+		if dst.ClipRect != (sdl.Rect{W: 200, H: 40}) {
+			t.Errorf("%s left dst's clip rect at %v", name, dst.ClipRect)
+		}
+	}
+
+	// a texture atlas clips the renderer the same way, for each way it draws
+	for _, geometry := range []bool{false, true} {
+		dst := newSurface(200, 40)
+		renderer, err := sdl.CreateSoftwareRenderer(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		atlas, err := font.NewTextureAtlas(renderer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		atlas.UseGeometry = geometry
+		if err := atlas.DrawLayout(layout, x, 5, 1, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+		renderer.Present()
+		first, last, ok := inkColumns(dst)
+		if !ok || first != x || last != x+capped-1 {
+			t.Errorf("DrawLayout with geometry %v wrote columns %d to %d, want %d to %d", geometry, first, last, x, x+capped-1)
+		}
+		if renderer.IsClipEnabled() {
+			t.Errorf("DrawLayout with geometry %v left the renderer clipped to %v", geometry, renderer.GetClipRect())
+		}
+		atlas.Destroy()
+		renderer.Destroy()
+	}
+}
+
+func TestTabSpacesMeasuredAsRendered(t *testing.T) {
+	for _, tabSpaces := range []int{0, 1, 2, 8} {
+		font := MakeDefaultFont()
+		font.TabSpaces = tabSpaces
+		spaces := tabSpaces
+		if spaces == 0 {
+			spaces = 4
+		}
+		space := font.getStringLen(" ")
+		for _, text := range []string{"\t", "a\tb", "\t\tx", "ab\tc\td"} {
+			surface := font.RenderString(text, 1, 1, 1)
+			ln := font.getStringLen(text)
+			layout, err := font.Layout(text, LayoutOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, _ := layout.Size()
+			if ln != int(surface.W) || w != int(surface.W) {
+				t.Errorf("TabSpaces %d: %q measures %d and lays out %d wide, but renders %d wide", tabSpaces, text, ln, w, surface.W)
+			}
+			if tabs := strings.Count(text, "\t"); ln != font.getStringLen(strings.ReplaceAll(text, "\t", ""))+tabs*spaces*space {
+				t.Errorf("TabSpaces %d: %q measures %d, not %d spaces a tab", tabSpaces, text, ln, spaces)
+			}
+		}
+	}
+}
+
+func TestLineHeights(t *testing.T) {
+	font := MakeDefaultFont()
+	font.NewlinePad = 5
+	lineWidth, _ := font.Measure("ab")
+	for _, test := range []struct {
+		text, words string
+		lines       int
+	}{
+		{"ab", "ab", 1},
+		{"ab\ncd", "ab cd", 2},
+		{"ab\ncd\nef", "ab cd ef", 3},
+	} {
+		// NewlinePad only goes between lines
+		want := test.lines*font.CharSize[1] + (test.lines-1)*font.NewlinePad
+		if h := font.RenderString(test.text, 1, 1, 1).H; int(h) != want {
+			t.Errorf("RenderString(%q) is %d tall, want %d", test.text, h, want)
+		}
+		if _, h := font.Measure(test.text); h != want {
+			t.Errorf("Measure(%q) is %d tall, want %d", test.text, h, want)
+		}
+
+		wrapped, err := font.Layout(test.words, LayoutOptions{MaxWidth: lineWidth})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := len(wrapped.Lines()); lines != test.lines {
+			t.Fatalf("%q wrapped at %d is %d lines, want %d", test.words, lineWidth, lines, test.lines)
+		}
+		if h := wrapped.Render(1, 1, 1).H; int(h) != want {
+			t.Errorf("%q wrapped at %d renders %d tall, want %d", test.words, lineWidth, h, want)
+		}
+		if h := font.RenderList([]string{test.words}, "", 0, lineWidth, 1, 1, 1).H; int(h) != want {
+			t.Errorf("RenderList of %q at %d is %d tall, want %d", test.words, lineWidth, h, want)
+		}
+	}
+}
+
+func TestNewlines(t *testing.T) {
+	font := MakeDefaultFont()
+	aWidth, _ := font.Measure("a")
+	for _, test := range []struct {
+		text   string
+		width  int
+		starts []int // the index each line starts at
+	}{
+		{"\n", 0, []int{0, 1}},
+		{"a\n", aWidth, []int{0, 2}},
+		{"a\n\nb", aWidth, []int{0, 2, 3}},
+		{"\n\n", 0, []int{0, 1, 2}},
+	} {
+		t.Run(fmt.Sprintf("%q", test.text), func(t *testing.T) {
+			// N newlines make N+1 lines, blank or not, each LineHeight tall
+			lines := len(test.starts)
+			height := lines*font.LineHeight() - font.NewlinePad
+			if w, h := font.Measure(test.text); w != test.width || h != height {
+				t.Errorf("Measure = %dx%d, want %dx%d", w, h, test.width, height)
+			}
+			if surface := font.RenderString(test.text, 1, 1, 1); int(surface.W) != test.width || int(surface.H) != height {
+				t.Errorf("RenderString is %dx%d, want %dx%d", surface.W, surface.H, test.width, height)
+			}
+			if n, w := font.MeasureReader(strings.NewReader(test.text), 0); n != lines || w != test.width {
+				t.Errorf("MeasureReader = %d lines %d wide, want %d lines %d wide", n, w, lines, test.width)
+			}
+			wrap := font.Wrap(test.text, 100)
+			if len(wrap.Lines) != lines {
+				t.Fatalf("Wrap made %d lines, want %d", len(wrap.Lines), lines)
+			}
+
+			layout, err := font.Layout(test.text, LayoutOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for line, start := range test.starts {
+				if got := wrap.Lines[line].Start; got != start {
+					t.Errorf("Wrap line %d starts at %d, want %d", line, got, start)
+				}
+				if got := layout.IndexAtPoint(0, line*font.LineHeight()+1); got != start {
+					t.Errorf("IndexAtPoint on line %d = %d, want %d", line, got, start)
+				}
+				if got := layout.LineStart(start); got != start {
+					t.Errorf("LineStart(%d) = %d, want it to start its own line", start, got)
+				}
+			}
+			if got := layout.IndexBelow(0, 0); lines > 1 && got != test.starts[1] {
+				t.Errorf("IndexBelow(0) = %d, want %d", got, test.starts[1])
+			}
+		})
+	}
+}
+
+func TestLayoutRendersMany(t *testing.T) {
+	font := MakeDefaultFont()
+	text := "Score: 100\nLives: 3"
+	layout, err := font.Layout(text, LayoutOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, color := range [][3]float64{{1, 1, 1}, {1, 0, 0}, {0.25, 0.5, 1}, {1, 1, 1}} {
+		want := font.RenderString(text, color[0], color[1], color[2])
+		assertSamePixels(t, fmt.Sprintf("Render in %v", color), layout.Render(color[0], color[1], color[2]), want)
+
+		dst := newSurface(int(want.W), int(want.H))
+		layout.RenderInto(dst, 0, 0, color[0], color[1], color[2])
+		assertSamePixels(t, fmt.Sprintf("RenderInto in %v", color), dst, want)
+	}
+}
+
+func BenchmarkRenderString(b *testing.B) {
+	font := MakeDefaultFont()
+	for i := 0; i < b.N; i++ {
+		font.RenderString("The quick brown fox\njumps over the lazy dog", 1, float64(i%2), 1).Free()
+	}
+}
+
+func BenchmarkLayoutRender(b *testing.B) {
+	font := MakeDefaultFont()
+	layout, err := font.Layout("The quick brown fox\njumps over the lazy dog", LayoutOptions{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		layout.Render(1, float64(i%2), 1).Free()
+	}
+}
+
+func TestWalkConformance(t *testing.T) {
+	font := MakeDefaultFont()
+	font.Ligatures = map[string]rune{"->": '☺'}
+	font.AdvanceOverride = map[rune]int{'i': 1}
+	font.Bearings = make([]int, len(font.CharWidths))
+	font.Bearings[strings.IndexRune(font.CharSet, 'j')] = -1
+	text := "Walking -> the jingle of\n  tinkling glyphs, centred"
+	width, _ := font.Measure("Walking -> the jingle")
+	for _, opts := range []LayoutOptions{
+		{},
+		{MaxWidth: width, Align: AlignCenter},
+		{MaxWidth: width, Align: AlignRight, TopPad: 3, FirstLineIndent: 4},
+		{MaxWidth: width, CollapseWhitespace: true, BalanceLines: 3},
+	} {
+		layout, err := font.Layout(text, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a custom renderer that only knows about the placements
+		w, h := layout.Size()
+		custom := newSurface(w, h)
+		font.Atlas.SetColorMod(255, 255, 255)
+		font.Atlas.SetBlendMode(sdl.BLENDMODE_BLEND)
+		runes := []rune(text)
+		layout.Walk(func(glyph GlyphPlacement) bool {
+			if glyph.Index >= 0 && runes[glyph.Index] != glyph.Char && glyph.Char != '☺' {
+				t.Errorf("%+v: glyph %q is at index %d, which is %q", opts, glyph.Char, glyph.Index, runes[glyph.Index])
+			}
+			src, dst := glyph.Src, glyph.Dst
+			font.Atlas.Blit(&src, custom, &dst)
+			return true
+		})
+		blitted := newSurface(w, h)
+		layout.RenderInto(blitted, 0, 0, 1, 1, 1)
+		assertSamePixels(t, fmt.Sprintf("%+v", opts), custom, blitted)
+
+		// compositing rounds faint edges by a level now and then
+		composited, pixels := rgba(layout.Render(1, 1, 1)), rgba(custom)
+		for i := range pixels {
+			if !near(pixels[i], composited[i]) {
+				t.Errorf("%+v: pixel %d,%d is %v, Render draws %v", opts, i%w, i/w, pixels[i], composited[i])
+				break
+			}
+		}
+	}
+}
+
+func TestHangingPunctuationWidth(t *testing.T) {
+	font := MakeDefaultFont()
+	font.HangingPunctuation = map[rune]int{'"': 3, '.': 2}
+	text := `"Hanging quotes, and a full stop."`
+	maxWidth, _ := font.Measure("Hanging quotes, and")
+
+	for _, align := range []Alignment{AlignLeft, AlignCenter, AlignRight} {
+		layout, err := font.Layout(text, LayoutOptions{MaxWidth: maxWidth, Width: maxWidth, Align: align})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w, _ := layout.Size(); w != maxWidth {
+			t.Errorf("align %d: the block is %d wide, want its Width of %d", align, w, maxWidth)
+		}
+		if surface := layout.Render(1, 1, 1); int(surface.W) != maxWidth {
+			t.Errorf("align %d: Render is %d wide, want %d", align, surface.W, maxWidth)
+		}
+		layout.Walk(func(glyph GlyphPlacement) bool {
+			if glyph.Dst.X < 0 {
+				t.Errorf("align %d: %q is drawn at %d, left of the box", align, glyph.Char, glyph.Dst.X)
+			}
+			return true
+		})
+	}
+}
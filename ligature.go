@@ -0,0 +1,42 @@
+package font
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ligatureTail is drawn for each rune of a ligature after its first, which
+// the ligature's glyph already covers
+const ligatureTail rune = -1
+
+// ligate returns what's drawn for each rune of runes: a ligature's glyph at
+// its first rune and ligatureTail for the rest, or the rune itself. Where
+// ligatures overlap the longest one wins, then the first. Ligatures whose
+// glyph the font doesn't have, or that hold whitespace, are never applied.
+func (font *Font) ligate(runes []rune) []rune {
+	if len(font.Ligatures) == 0 {
+		return runes
+	}
+	longest := 0
+	for seq := range font.Ligatures {
+		longest = max(longest, utf8.RuneCountInString(seq))
+	}
+
+	glyphs := make([]rune, len(runes))
+	for i := 0; i < len(runes); {
+		n, glyph := 1, runes[i]
+		for length := min(longest, len(runes)-i); length >= 2; length-- {
+			seq := string(runes[i : i+length])
+			if target, ok := font.Ligatures[seq]; ok && font.glyphIndex(target) >= 0 && !strings.ContainsAny(seq, " \t\n") {
+				n, glyph = length, target
+				break
+			}
+		}
+		glyphs[i] = glyph
+		for j := 1; j < n; j++ {
+			glyphs[i+j] = ligatureTail
+		}
+		i += n
+	}
+	return glyphs
+}
@@ -0,0 +1,95 @@
+package font
+
+import (
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// RenderList draws items as a list, each starting with marker, such as "•"
+// or "-", followed by gap pixels. Items wrap at maxWidth, counting the marker,
+// and their wrapped lines are indented to line up with the text after the
+// marker rather than the marker itself. A maxWidth of 0 only breaks at
+// newlines.
+func (font *Font) RenderList(items []string, marker string, gap, maxWidth int, r, g, b float64) *sdl.Surface {
+	bullet := font.layout(marker, LayoutOptions{})
+	indent := bullet.width + gap
+
+	opts := LayoutOptions{}
+	if maxWidth > 0 {
+		opts.MaxWidth = max(maxWidth-indent, 1)
+	}
+	layouts := make([]*Layout, len(items))
+	width, lines := 0, 0
+	for i, item := range items {
+		layouts[i] = font.layout(item, opts)
+		width = max(width, bullet.width, indent+layouts[i].width)
+		lines += len(layouts[i].wrap.Lines)
+	}
+
+	surface := font.outputSurface(width, max(lines*font.LineHeight()-font.NewlinePad, 0))
+	tint := sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+	y := 0
+	for _, layout := range layouts {
+		bullet.composite(surface, 0, y, tint)
+		layout.composite(surface, indent, y, tint)
+		y += len(layout.wrap.Lines) * font.LineHeight()
+	}
+	return surface
+}
+
+// RenderList draws items as a list with renderer the way Font.RenderList
+// does, wrapping them with WrapLines
+func RenderList(renderer TextRenderer, items []string, marker string, gap, maxWidth int, r, g, b float64) (*sdl.Surface, error) {
+	if font, ok := renderer.(*Font); ok {
+		return font.RenderList(items, marker, gap, maxWidth, r, g, b), nil
+	}
+	bulletW, _ := renderer.Measure(marker)
+	indent := bulletW + gap
+	itemWidth := 0
+	if maxWidth > 0 {
+		itemWidth = max(maxWidth-indent, 1)
+	}
+
+	// each item's wrapped text and where it goes
+	type placed struct {
+		text string
+		y    int
+	}
+	placedItems := make([]placed, len(items))
+	width, height, y := 0, 0, 0
+	for i, item := range items {
+		lines := WrapLines(renderer, item, itemWidth)
+		text := strings.Join(lines, "\n")
+		w, h := renderer.Measure(text)
+		width = max(width, bulletW, indent+w)
+		height = max(height, y+h)
+		placedItems[i] = placed{text, y}
+		y += len(lines) * renderer.LineHeight()
+	}
+
+	surface := newSurface(width, height)
+	draw := func(text string, x, y int) error {
+		if text == "" {
+			return nil
+		}
+		drawn, err := renderer.Render(text, r, g, b)
+		if err != nil || drawn == nil {
+			return err
+		}
+		defer drawn.Free()
+		copyPixels(drawn, nil, surface, &sdl.Rect{X: int32(x), Y: int32(y)})
+		return nil
+	}
+	for _, item := range placedItems {
+		if err := draw(marker, 0, item.y); err != nil {
+			surface.Free()
+			return nil, err
+		}
+		if err := draw(item.text, indent, item.y); err != nil {
+			surface.Free()
+			return nil, err
+		}
+	}
+	return surface, nil
+}
@@ -0,0 +1,107 @@
+package font
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// the runes RenderNumber can draw
+const numberRunes = "0123456789:."
+
+// MaxScaledSize is the largest width or height, in pixels, of a surface
+// RenderNumber will create. It's well below where int32 sizes overflow and
+// within the texture size limit of most GPUs.
+const MaxScaledSize = 16384
+
+type numberKey struct {
+	r, g, b uint8
+	scale   int
+}
+
+// pre-rendered glyphs for one color and scale, indexed like numberRunes
+type numberCells struct {
+	cells    [len(numberRunes)]*sdl.Surface
+	advances [len(numberRunes)]int
+	bearings [len(numberRunes)]int
+	height   int
+}
+
+// numberCellsFor returns the cached digit glyphs for a color and scale, rendering them the first time
+func (font *Font) numberCellsFor(key numberKey) *numberCells {
+	if cached, ok := font.numberCache[key]; ok {
+		return cached
+	}
+
+	cached := &numberCells{height: font.CharSize[1] * key.scale}
+	tint := sdl.Color{R: key.r, G: key.g, B: key.b, A: 255}
+	for i, char := range numberRunes {
+		adv, _ := font.advance(char)
+		cached.advances[i] = adv * key.scale
+		cached.bearings[i] = font.bearing(char) * key.scale
+
+		src, ok := font.loadGlyph(char)
+		cell := newSurface(int(src.W)*key.scale, cached.height)
+		if ok && src.W > 0 {
+			font.enlargeGlyph(src, cell, 0, font.glyphTop(char)*key.scale, key.scale, tint)
+		}
+		cached.cells[i] = cell
+	}
+
+	if font.numberCache == nil {
+		font.numberCache = make(map[numberKey]*numberCells)
+	}
+	font.numberCache[key] = cached
+	return cached
+}
+
+// RenderNumber draws a value made of digits, ':' and '.' from glyphs cached
+// per color and scale, which is much cheaper than RenderString for values
+// like timers and scores that change every frame. Scales SmoothScales
+// generated an atlas for are drawn from it. It returns an error rather
+// than a surface wider or taller than MaxScaledSize. The cache is kept in
+// the font, so unlike Render it mustn't be called on the same font from more
+// than one goroutine at once.
+func (font *Font) RenderNumber(value string, scale int, r, g, b float64) (*sdl.Surface, error) {
+	scale = max(scale, 1)
+	if scale > MaxScaledSize/max(font.CharSize[0], font.CharSize[1], 1) {
+		return nil, fmt.Errorf("RenderNumber: scale %d makes %dx%d cells bigger than %d pixels", scale, font.CharSize[0], font.CharSize[1], MaxScaledSize)
+	}
+	cached := font.numberCellsFor(numberKey{uint8(r * 255), uint8(g * 255), uint8(b * 255), scale})
+
+	indices := make([]int, 0, len(value))
+	width := 0
+	for _, char := range value {
+		i := strings.IndexRune(numberRunes, char)
+		if i < 0 {
+			return nil, fmt.Errorf("RenderNumber: %q is not a digit, ':' or '.'", char)
+		}
+		indices = append(indices, i)
+		width += cached.advances[i]
+		if width > MaxScaledSize {
+			return nil, fmt.Errorf("RenderNumber: %d runes at scale %d are wider than %d pixels", utf8.RuneCountInString(value), scale, MaxScaledSize)
+		}
+	}
+
+	surface := font.outputSurface(width, cached.height)
+	cursorX := 0
+	for _, i := range indices {
+		dst := sdl.Rect{X: int32(max(cursorX+cached.bearings[i], 0))}
+		cached.cells[i].Blit(nil, surface, &dst)
+		cursorX += cached.advances[i]
+	}
+	return surface, nil
+}
+
+// ClearNumberCache frees the glyphs cached by RenderNumber. Call it after
+// changing the atlas or metrics of a font that has rendered numbers.
+func (font *Font) ClearNumberCache() {
+	for _, cached := range font.numberCache {
+		for _, cell := range cached.cells {
+			cell.Free()
+		}
+	}
+	font.numberCache = nil
+}
@@ -0,0 +1,74 @@
+package font
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRenderNumber(t *testing.T) {
+	font := MakeDefaultFont()
+	for _, value := range []string{"0", "12:34.56", "9876543210", ""} {
+		surface, err := font.RenderNumber(value, 1, 1, 0.5, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSamePixels(t, fmt.Sprintf("RenderNumber(%q)", value), surface, font.RenderString(value, 1, 0.5, 0))
+
+		// larger scales are the same pixels, enlarged
+		scaled, err := font.RenderNumber(value, 3, 1, 0.5, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scaled.W != surface.W*3 || scaled.H != surface.H*3 {
+			t.Fatalf("RenderNumber(%q) at scale 3 is %dx%d, want %dx%d", value, scaled.W, scaled.H, surface.W*3, surface.H*3)
+		}
+		small, large := rgba(surface), rgba(scaled)
+		for i, p := range large {
+			x, y := i%int(scaled.W), i/int(scaled.W)
+			if want := small[y/3*int(surface.W)+x/3]; p != want {
+				t.Fatalf("RenderNumber(%q) at scale 3: pixel %d,%d is %v, want %v", value, x, y, p, want)
+			}
+		}
+	}
+	if len(font.numberCache) != 2 {
+		t.Errorf("%d color and scale pairs cached, want 2", len(font.numberCache))
+	}
+
+	// the cells are tinted as they're copied, not through the shared atlas
+	font.ClearNumberCache()
+	font.Atlas.SetColorMod(10, 20, 30)
+	if _, err := font.RenderNumber("12", 2, 1, 0.5, 0); err != nil {
+		t.Fatal(err)
+	}
+	assertColorMod(t, "RenderNumber", font.Atlas, 10, 20, 30)
+	font.Atlas.SetColorMod(255, 255, 255)
+
+	for _, value := range []string{"12a", "-1", "1 2"} {
+		if _, err := font.RenderNumber(value, 1, 1, 1, 1); err == nil {
+			t.Errorf("RenderNumber(%q) succeeded", value)
+		}
+	}
+	if _, err := font.RenderNumber("1", MaxScaledSize, 1, 1, 1); err == nil {
+		t.Error("RenderNumber at scale MaxScaledSize succeeded")
+	}
+}
+
+// timer returns the i-th value of a timer that changes every call
+func timer(i int) string {
+	return fmt.Sprintf("%02d:%02d.%02d", i/6000%60, i/100%60, i%100)
+}
+
+func BenchmarkRenderNumberTimer(b *testing.B) {
+	font := MakeDefaultFont()
+	for i := 0; i < b.N; i++ {
+		surface, _ := font.RenderNumber(timer(i), 1, 1, 1, 1)
+		surface.Free()
+	}
+}
+
+func BenchmarkRenderStringTimer(b *testing.B) {
+	font := MakeDefaultFont()
+	for i := 0; i < b.N; i++ {
+		font.RenderString(timer(i), 1, 1, 1).Free()
+	}
+}
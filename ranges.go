@@ -0,0 +1,76 @@
+package font
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/veandco/go-sdl2/img"
+)
+
+// Ranges declares a charset as inclusive ranges of code points in atlas order,
+// e.g. Ranges{{0x20, 0x7E}, {0x2500, 0x257F}, {'☺', '☺'}}. A single rune is a
+// range that starts and ends with it. Ranges must be ascending and must not
+// overlap.
+type Ranges [][2]rune
+
+// Validate checks that every range is ascending, and that ranges come in
+// ascending order without overlapping
+func (ranges Ranges) Validate() error {
+	for i, r := range ranges {
+		if r[0] > r[1] {
+			return fmt.Errorf("range %d (%U-%U) ends before it starts", i, r[0], r[1])
+		}
+		if i > 0 && r[0] <= ranges[i-1][1] {
+			prev := ranges[i-1]
+			return fmt.Errorf("range %d (%U-%U) overlaps or comes before range %d (%U-%U)", i, r[0], r[1], i-1, prev[0], prev[1])
+		}
+	}
+	return nil
+}
+
+// Len is the number of runes the ranges cover
+func (ranges Ranges) Len() (n int) {
+	for _, r := range ranges {
+		n += int(r[1]-r[0]) + 1
+	}
+	return
+}
+
+// CharSet expands the ranges into a charset string
+func (ranges Ranges) CharSet() (string, error) {
+	if err := ranges.Validate(); err != nil {
+		return "", err
+	}
+	var charSet strings.Builder
+	for _, r := range ranges {
+		for char := r[0]; char <= r[1]; char++ {
+			charSet.WriteRune(char)
+		}
+	}
+	return charSet.String(), nil
+}
+
+// NewFontFromRanges creates a Font like NewFont with its charset declared as
+// ranges. charWidths has a width for every rune the ranges cover, or is nil
+// to give them all defaultWidth.
+func NewFontFromRanges(atlasPath string, gridWidth int, ranges Ranges, charWidths []int, defaultWidth int) (Font, error) {
+	charSet, err := ranges.CharSet()
+	if err != nil {
+		return Font{}, err
+	}
+
+	if charWidths == nil {
+		charWidths = make([]int, ranges.Len())
+		for i := range charWidths {
+			charWidths[i] = defaultWidth
+		}
+	} else if len(charWidths) != ranges.Len() {
+		return Font{}, fmt.Errorf("%d widths for %d characters", len(charWidths), ranges.Len())
+	}
+
+	atlas, err := img.Load(atlasPath)
+	if err != nil {
+		return Font{}, err
+	}
+	return fontFromAtlas(atlas, gridWidth, charSet, charWidths), nil
+}
@@ -0,0 +1,113 @@
+package font
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// GlyphSource is where a glyph is in the atlas and how far it advances the
+// cursor, for atlases whose glyphs aren't laid out on a uniform grid
+type GlyphSource struct {
+	Rect    sdl.Rect
+	Advance int // cursor movement past the glyph, LetterPad included
+	Top     int // rows between the top of the line and the rect, for glyphs trimmed of empty space
+}
+
+// NewFontFromRects creates a Font whose glyphs are read from explicit rects in
+// the atlas, such as one made by a packer, rather than grid cells. glyphs has
+// an entry for every rune of charSet. CharSize[1] is still the line height, so
+// rects shorter than it are drawn from the top of the line.
+func NewFontFromRects(atlasPath string, charSet string, glyphs []GlyphSource) (Font, error) {
+	if count := utf8.RuneCountInString(charSet); len(glyphs) != count {
+		return Font{}, fmt.Errorf("%d rects for %d characters", len(glyphs), count)
+	}
+	atlas, err := img.Load(atlasPath)
+	if err != nil {
+		return Font{}, err
+	}
+	font := fontFromAtlas(atlas, 1, charSet, nil)
+	font.Rects = glyphs
+	return font, nil
+}
+
+// RectTable returns the source rect and advance of every rune in the charset,
+// worked out from the grid for fonts that don't have a rect table. Setting a
+// grid font's Rects to it doesn't change how the font renders.
+func (font *Font) RectTable() []GlyphSource {
+	charSet := []rune(font.CharSet)
+	table := make([]GlyphSource, len(charSet))
+	for index, char := range charSet {
+		table[index].Rect, _ = font.glyphRect(char)
+		if font.Rects == nil {
+			table[index].Advance = font.gridAdvance(index, char)
+		} else if index < len(font.Rects) {
+			table[index].Advance = font.Rects[index].Advance
+			table[index].Top = font.Rects[index].Top
+		}
+	}
+	return table
+}
+
+// glyphTop returns how far below the top of the line char's rect is drawn
+func (font *Font) glyphTop(char rune) int {
+	if index := font.glyphIndex(char); index >= 0 && index < len(font.Rects) {
+		return font.Rects[index].Top
+	}
+	return 0
+}
+
+// LoadStripFont creates a Font from a single row strip of glyphs as tall as
+// the image, separated by columns entirely of the separator color, like many
+// homebrew fonts. Each segment between separators is a glyph of that width,
+// in charset order, and there must be one for every rune of charSet.
+func LoadStripFont(path string, charSet string, separator sdl.Color) (*Font, error) {
+	atlas, err := img.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if atlas.MustLock() {
+		atlas.Lock()
+	}
+	pixels := atlas.Pixels()
+	isSeparator := func(x int) bool {
+		for y := 0; y < int(atlas.H); y++ {
+			r, g, b, a := sdl.GetRGBA(getPixel(atlas, pixels, x, y), atlas.Format)
+			if (sdl.Color{R: r, G: g, B: b, A: a}) != separator {
+				return false
+			}
+		}
+		return true
+	}
+	var glyphs []GlyphSource
+	start := 0
+	for x := 0; x <= int(atlas.W); x++ {
+		if x < int(atlas.W) && !isSeparator(x) {
+			continue
+		}
+		if x > start {
+			glyphs = append(glyphs, GlyphSource{Rect: sdl.Rect{X: int32(start), W: int32(x - start), H: atlas.H}})
+		}
+		start = x + 1
+	}
+	if atlas.MustLock() {
+		atlas.Unlock()
+	}
+
+	if count := utf8.RuneCountInString(charSet); len(glyphs) != count {
+		atlas.Free()
+		return nil, fmt.Errorf("strip font %s: %d glyphs between separators for %d characters", path, len(glyphs), count)
+	}
+
+	font := fontFromAtlas(atlas, 1, charSet, nil)
+	font.CharSize = [2]int{0, int(atlas.H)}
+	for i := range glyphs {
+		glyphs[i].Advance = int(glyphs[i].Rect.W) + font.LetterPad
+		font.CharSize[0] = max(font.CharSize[0], int(glyphs[i].Rect.W))
+	}
+	font.Rects = glyphs
+	return &font, nil
+}
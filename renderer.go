@@ -0,0 +1,107 @@
+package font
+
+import (
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// TextRenderer is the part of a Font that code laying out UI needs. Depending
+// on it instead of *Font lets tests substitute a fake with fixed metrics
+// without initializing SDL or loading an atlas.
+type TextRenderer interface {
+	// Measure returns the size of the surface Render would return for text
+	Measure(text string) (w, h int)
+	// LineHeight is the distance between the tops of two consecutive lines
+	LineHeight() int
+	// Render draws text, which may span several lines, onto a new surface
+	Render(text string, r, g, b float64) (*sdl.Surface, error)
+}
+
+var _ TextRenderer = (*Font)(nil)
+
+// Measure returns the width of the widest line of text and the height of all of its lines
+func (font *Font) Measure(text string) (w, h int) {
+	return font.layout(text, LayoutOptions{}).Size()
+}
+
+// Render draws text onto a new surface, one line per newline
+func (font *Font) Render(text string, r, g, b float64) (*sdl.Surface, error) {
+	return font.layout(text, LayoutOptions{}).Render(r, g, b), nil
+}
+
+// WrapLines breaks text into lines no wider than maxWidth as renderer
+// measures them, so code holding only a TextRenderer can wrap text too. Lines
+// break where Font.Wrap breaks them: at runs of spaces and tabs, after
+// hyphens, and inside words too wide for a line, with a hyphen added when the
+// renderer draws one. A maxWidth of 0 only breaks at newlines. A *Font is
+// wrapped by Font.Wrap itself, so ligatures and kerning are counted too.
+func WrapLines(renderer TextRenderer, text string, maxWidth int) []string {
+	var wrap *WrapResult
+	if font, ok := renderer.(*Font); ok {
+		wrap = font.Wrap(text, maxWidth)
+	} else {
+		wrap = wrapMeasured(renderer, text, maxWidth)
+	}
+	lines := make([]string, len(wrap.Lines))
+	for i := range wrap.Lines {
+		lines[i] = wrap.LineText(i)
+	}
+	return lines
+}
+
+// wrapMeasured wraps text like Font.Wrap, measuring each rune with renderer
+func wrapMeasured(renderer TextRenderer, text string, maxWidth int) *WrapResult {
+	wrap := &WrapResult{Text: []rune(text), MaxWidth: maxWidth}
+	m := measuredMetrics{renderer: renderer, runes: wrap.Text, widths: make(map[rune]int)}
+	for start := 0; start <= len(wrap.Text); {
+		end := start
+		for end < len(wrap.Text) && wrap.Text[end] != '\n' {
+			end++
+		}
+		kind := BreakNewline
+		if end == len(wrap.Text) {
+			kind = BreakEnd
+		}
+		wrapParagraph(m, wrap, start, end, kind, maxWidth)
+		start = end + 1
+	}
+	return wrap
+}
+
+// measuredMetrics are what a TextRenderer measures each rune as on its own
+type measuredMetrics struct {
+	renderer TextRenderer
+	runes    []rune
+	widths   map[rune]int // measured so far
+}
+
+func (m measuredMetrics) width(char rune) int {
+	w, ok := m.widths[char]
+	if !ok {
+		w, _ = m.renderer.Measure(string(char))
+		m.widths[char] = w
+	}
+	return w
+}
+
+func (m measuredMetrics) advance(i int) int { return m.width(m.runes[i]) }
+
+func (m measuredMetrics) breakSpace(i int) bool { return isBreakSpace(m.runes[i]) }
+
+func (m measuredMetrics) breakAfter(i int) bool { return m.runes[i] == '-' }
+
+func (m measuredMetrics) hang(i int) int { return 0 }
+
+func (m measuredMetrics) joined(i int) bool { return i > 0 && joinsPrevious(m.runes[i-1], m.runes[i]) }
+
+func (m measuredMetrics) hyphen() (int, bool) {
+	w := m.width('-')
+	return w, w > 0
+}
+
+// RenderWrapped draws text wrapped at maxWidth by WrapLines with renderer,
+// one line below another
+func RenderWrapped(renderer TextRenderer, text string, maxWidth int, r, g, b float64) (*sdl.Surface, error) {
+	return renderer.Render(strings.Join(WrapLines(renderer, text, maxWidth), "\n"), r, g, b)
+}
@@ -0,0 +1,130 @@
+package font
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// fixedRenderer is a TextRenderer whose every rune is 2px wide, with no SDL
+type fixedRenderer struct{}
+
+func (fixedRenderer) Measure(text string) (w, h int) {
+	for _, line := range strings.Split(text, "\n") {
+		w = max(w, 2*len([]rune(line)))
+	}
+	return w, strings.Count(text, "\n")*3 + 3
+}
+
+func (fixedRenderer) LineHeight() int { return 3 }
+
+func (fixedRenderer) Render(text string, r, g, b float64) (*sdl.Surface, error) {
+	return nil, nil
+}
+
+// recordingRenderer is a fixedRenderer that records the text it renders
+type recordingRenderer struct {
+	fixedRenderer
+	drawn *[]string
+}
+
+func (r recordingRenderer) Render(text string, _, _, _ float64) (*sdl.Surface, error) {
+	*r.drawn = append(*r.drawn, text)
+	return nil, nil
+}
+
+func TestWrapLines(t *testing.T) {
+	for _, test := range []struct {
+		text     string
+		maxWidth int
+		want     []string
+	}{
+		{"one two three", 0, []string{"one two three"}},
+		{"one two three", 14, []string{"one two", "three"}},
+		{"one two three", 6, []string{"one", "two", "th-", "ree"}},
+		{"one\ntwo three", 100, []string{"one", "two three"}},
+		{"unbreakable a", 10, []string{"unbr-", "eaka-", "ble a"}},
+		{"one\ttwo three", 12, []string{"one", "two", "three"}},
+		{"well-known", 14, []string{"well-", "known"}},
+		{"", 10, []string{""}},
+	} {
+		if got := WrapLines(fixedRenderer{}, test.text, test.maxWidth); !slices.Equal(got, test.want) {
+			t.Errorf("WrapLines(%q, %d) = %q, want %q", test.text, test.maxWidth, got, test.want)
+		}
+	}
+}
+
+// measuredFont is a TextRenderer that only measures and draws with a font,
+// so WrapLines can't tell it's one
+type measuredFont struct{ TextRenderer }
+
+func TestWrapLinesLikeFontWrap(t *testing.T) {
+	font := MakeDefaultFont()
+	text := "A well-known\ttab, and an extraordinarily long word\n\nthen more text"
+	for _, width := range []int{0, 20, 37, 60, 100} {
+		want := WrapLines(&font, text, width)
+		if got := WrapLines(measuredFont{&font}, text, width); !slices.Equal(got, want) {
+			t.Errorf("at %d WrapLines = %q, Font.Wrap breaks it into %q", width, got, want)
+		}
+	}
+}
+
+func TestWrapLinesFont(t *testing.T) {
+	font := MakeDefaultFont()
+	width, _ := font.Measure("Hello there")
+	lines := WrapLines(&font, "Hello there world", width)
+	if !slices.Equal(lines, []string{"Hello there", "world"}) {
+		t.Errorf("WrapLines = %q", lines)
+	}
+	surface, err := RenderWrapped(&font, "Hello there world", width, 1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSamePixels(t, "RenderWrapped", surface, font.RenderString("Hello there\nworld", 1, 1, 1))
+}
+
+func TestRenderListRenderer(t *testing.T) {
+	font := MakeDefaultFont()
+	items := []string{"first item", "a second, longer item that wraps", "well-known"}
+	for _, maxWidth := range []int{0, 40, 70} {
+		want := font.RenderList(items, "-", 2, maxWidth, 1, 0.5, 0)
+		got, err := RenderList(measuredFont{&font}, items, "-", 2, maxWidth, 1, 0.5, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSamePixels(t, fmt.Sprintf("RenderList at %d", maxWidth), got, want)
+	}
+}
+
+// coveredFont is a measuredFont that also says which runes it can't draw
+type coveredFont struct {
+	measuredFont
+	font *Font
+}
+
+func (f coveredFont) MissingRunes(text string) []rune { return f.font.MissingRunes(text) }
+
+func TestRenderCheckboxRenderer(t *testing.T) {
+	font := MakeDefaultFont()
+	for _, checked := range []bool{false, true} {
+		got, err := RenderCheckbox(coveredFont{measuredFont{&font}, &font}, checked, 1, 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSamePixels(t, fmt.Sprintf("checked %v", checked), got, font.RenderCheckbox(checked, 1, 1, 1))
+	}
+	// with no way to tell, the first pair is drawn
+	var drawn []string
+	recorder := recordingRenderer{drawn: &drawn}
+	for _, checked := range []bool{false, true} {
+		if _, err := RenderCheckbox(recorder, checked, 1, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !slices.Equal(drawn, []string{"☐", "☑"}) {
+		t.Errorf("RenderCheckbox drew %q, want the first default pair", drawn)
+	}
+}
@@ -0,0 +1,175 @@
+package font
+
+import (
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// RuneRange is a range [Start, End) of rune indices into a string
+type RuneRange struct {
+	Start, End int
+}
+
+// RunesInRect returns, for each laid out line that rect overlaps vertically,
+// the range of runes selected by rect. A rune is selected when the horizontal
+// center of its cell lies inside rect, so a glyph counts once the majority of
+// it is covered. Lines the rect touches but selects nothing on give an empty
+// range, keeping the result aligned with the lines on screen.
+func (font *Font) RunesInRect(text string, opts LayoutOptions, rect sdl.Rect) []RuneRange {
+	return font.layout(text, opts).RunesInRect(rect)
+}
+
+// RunesInRect is RunesInRect for text that has already been laid out
+func (layout *Layout) RunesInRect(rect sdl.Rect) []RuneRange {
+	var ranges []RuneRange
+
+	for i, line := range layout.wrap.Lines {
+		box := layout.LineRect(i)
+		if box.Y >= rect.Y+rect.H || box.Y+box.H <= rect.Y {
+			continue
+		}
+
+		selected := RuneRange{Start: -1}
+		for index := line.Start; index < line.End; index++ {
+			cell := layout.cells[index]
+			center := cell.X + cell.W/2
+			if center < rect.X || center >= rect.X+rect.W {
+				continue
+			}
+			if selected.Start < 0 {
+				selected.Start = index
+			}
+			selected.End = index + 1
+		}
+		if selected.Start < 0 {
+			// nothing selected, so report an empty range where the rect starts
+			start := line.Start
+			for start < line.End && layout.cells[start].X+layout.cells[start].W/2 < rect.X {
+				start++
+			}
+			selected = RuneRange{Start: start, End: start}
+		}
+		ranges = append(ranges, layout.sourceRange(selected))
+	}
+
+	return ranges
+}
+
+// sourceRange maps a range of the laid out text to the text passed in
+func (layout *Layout) sourceRange(r RuneRange) RuneRange {
+	if r.End == r.Start {
+		start := layout.SourceIndex(r.Start)
+		return RuneRange{Start: start, End: start}
+	}
+	return RuneRange{Start: layout.SourceIndex(r.Start), End: layout.sourceEnd(r.End - 1)}
+}
+
+// TextInRect returns the text selected by RunesInRect, one line of the
+// selection per line of the result
+func (font *Font) TextInRect(text string, opts LayoutOptions, rect sdl.Rect) string {
+	return font.layout(text, opts).TextInRect(rect)
+}
+
+// TextInRect is TextInRect for text that has already been laid out
+func (layout *Layout) TextInRect(rect sdl.Rect) string {
+	text := layout.wrap.Text
+	if layout.original != nil {
+		text = layout.original
+	}
+	var lines []string
+	for _, selected := range layout.RunesInRect(rect) {
+		lines = append(lines, string(text[selected.Start:selected.End]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clampRange clamps a range of runes of the text passed in to its bounds
+func (layout *Layout) clampRange(start, end int) (int, int) {
+	n := len(layout.wrap.Text)
+	if layout.original != nil {
+		n = len(layout.original)
+	}
+	start = min(max(start, 0), n)
+	return start, min(max(end, start), n)
+}
+
+// RangeRect returns the box covering the cells and glyphs of runes
+// [start, end) of the text passed in, relative to the text origin. A range
+// with nothing drawn in it gives an empty rect.
+func (layout *Layout) RangeRect(start, end int) sdl.Rect {
+	start, end = layout.clampRange(start, end)
+	var bounds sdl.Rect
+	grow := func(r sdl.Rect) {
+		if r.W <= 0 || r.H <= 0 {
+			return
+		}
+		if bounds.Empty() {
+			bounds = r
+		} else {
+			bounds = bounds.Union(&r)
+		}
+	}
+	for i, cell := range layout.cells {
+		if index := layout.SourceIndex(i); index >= start && index < end {
+			grow(cell)
+		}
+	}
+	for _, glyph := range layout.glyphs {
+		if glyph.Index >= start && glyph.Index < end {
+			grow(glyph.Dst)
+		}
+	}
+	return bounds
+}
+
+// SubstringBounds returns the box each line of text, as RenderString lays it
+// out, takes up for runes [start, end) of it, one box for each line the range
+// spans. Boxes cover the runes' advances, and indices are clamped to the text.
+func (font *Font) SubstringBounds(text string, start, end int) []sdl.Rect {
+	return font.layout(text, LayoutOptions{}).SubstringBounds(start, end)
+}
+
+// SubstringBounds is SubstringBounds for text that has already been laid out
+func (layout *Layout) SubstringBounds(start, end int) []sdl.Rect {
+	start, end = layout.clampRange(start, end)
+	var boxes []sdl.Rect
+	for _, line := range layout.wrap.Lines {
+		var box sdl.Rect
+		found := false
+		for i := line.Start; i < line.End; i++ {
+			if index := layout.SourceIndex(i); index < start || index >= end {
+				continue
+			}
+			if cell := layout.cells[i]; !found {
+				box, found = cell, true
+			} else {
+				box = box.Union(&cell)
+			}
+		}
+		if found {
+			boxes = append(boxes, box)
+		}
+	}
+	return boxes
+}
+
+// RenderRange lays out all of text but only draws runes [startRune, endRune),
+// onto a surface covering just their RangeRect, so a highlighted or animated
+// span can be drawn separately and still line up with the rest of the text.
+// Indices are clamped to the text.
+func (font *Font) RenderRange(text string, startRune, endRune int, r, g, b float64) *sdl.Surface {
+	return font.layout(text, LayoutOptions{}).RenderRange(startRune, endRune, r, g, b)
+}
+
+// RenderRange is RenderRange for text that has already been laid out
+func (layout *Layout) RenderRange(start, end int, r, g, b float64) *sdl.Surface {
+	start, end = layout.clampRange(start, end)
+	bounds := layout.RangeRect(start, end)
+	surface := layout.font.outputSurface(layout.capWidth(int(bounds.X), int(bounds.W)), int(bounds.H))
+	tint := sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+	layout.compositeGlyphs(surface, -int(bounds.X), -int(bounds.Y), tint, func(glyph GlyphPlacement) bool {
+		return glyph.Index >= start && glyph.Index < end
+	})
+	return surface
+}
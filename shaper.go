@@ -0,0 +1,109 @@
+package font
+
+import "fmt"
+
+// ShapedGlyph is a glyph a shaper wants drawn for a cluster of runes
+type ShapedGlyph struct {
+	Glyph      rune   // rune whose glyph is drawn, which needn't appear in the text, such as a private use rune for a contextual form
+	Cluster    int    // index of the first rune of the text the glyph is drawn for
+	Advance    int    // how far the cursor moves past the glyph, when HasAdvance is set
+	HasAdvance bool   // use Advance instead of the font's advance for Glyph
+	Offset     [2]int // x and y offset the glyph is drawn at, relative to where the font would draw it
+}
+
+// SetShaper makes layout, wrapping, measuring and rendering draw what shaper
+// returns for text instead of its runes, or restores the default with nil.
+// The shaper replaces the font's Ligatures.
+//
+// A shaper is given the runes of a text, or of a paragraph of it, and returns
+// its glyphs in text order. Each glyph's Cluster is the index of the first
+// rune it's drawn for, and it's drawn for every rune up to the next glyph's
+// Cluster, or to the end for the last glyph. The first glyph's Cluster must be
+// 0 and each must be greater than the last, so every rune belongs to exactly
+// one glyph. A cluster's runes share one cell for hit testing, so carets land
+// before the cluster and selections take all of it or none, and
+// GlyphPlacement.Index is its Cluster. Lines break at spaces, tabs, newlines
+// and hyphens in the runes as they do without a shaper, so each of those
+// should be a cluster of its own; lines never break inside other clusters.
+//
+// Layout returns an error for text a shaper breaks the contract on, and
+// everything else that can't return one draws that text as if there were no
+// shaper.
+func (font *Font) SetShaper(shaper func(runes []rune) []ShapedGlyph) {
+	font.shaper = shaper
+}
+
+// shape returns what's drawn for each rune of runes, with ligatureTail as the
+// glyph of every rune of a cluster after its first. Without a shaper, clusters
+// are the font's ligatures, unless ligatures is false. If the shaper breaks
+// its contract, the runes are shaped as if there were no shaper and the error
+// says how it broke it.
+func (font *Font) shape(runes []rune, ligatures bool) ([]ShapedGlyph, error) {
+	shaped := make([]ShapedGlyph, len(runes))
+	if font.shaper != nil {
+		glyphs := font.shaper(runes)
+		if err := checkShaped(glyphs, len(runes)); err != nil {
+			unshaped := *font
+			unshaped.shaper = nil
+			shaped, _ = unshaped.shape(runes, ligatures)
+			return shaped, err
+		}
+		for i, glyph := range glyphs {
+			end := len(runes)
+			if i+1 < len(glyphs) {
+				end = glyphs[i+1].Cluster
+			}
+			shaped[glyph.Cluster] = glyph
+			for j := glyph.Cluster + 1; j < end; j++ {
+				shaped[j] = ShapedGlyph{Glyph: ligatureTail, Cluster: glyph.Cluster}
+			}
+		}
+	} else {
+		glyphs := runes
+		if ligatures {
+			glyphs = font.ligate(runes)
+		}
+		cluster := 0
+		for i, glyph := range glyphs {
+			if glyph != ligatureTail {
+				cluster = i
+			}
+			shaped[i] = ShapedGlyph{Glyph: glyph, Cluster: cluster}
+		}
+	}
+	if font.Overstrike {
+		font.overstrike(runes, shaped)
+	}
+	return shaped, nil
+}
+
+// checkShaped returns an error if the glyphs a shaper returned for n runes
+// break the contract SetShaper describes
+func checkShaped(glyphs []ShapedGlyph, n int) error {
+	if n > 0 && len(glyphs) == 0 {
+		return fmt.Errorf("shaper returned no glyphs for %d runes", n)
+	}
+	if n == 0 && len(glyphs) > 0 {
+		return fmt.Errorf("shaper returned %d glyphs for no runes", len(glyphs))
+	}
+	for i, glyph := range glyphs {
+		switch {
+		case i == 0 && glyph.Cluster != 0:
+			return fmt.Errorf("shaper's first glyph has cluster %d, not 0", glyph.Cluster)
+		case glyph.Cluster >= n:
+			return fmt.Errorf("shaper's glyph %d has cluster %d, past the end of %d runes", i, glyph.Cluster, n)
+		case i > 0 && glyph.Cluster <= glyphs[i-1].Cluster:
+			return fmt.Errorf("shaper's glyph %d has cluster %d, not after glyph %d's cluster %d", i, glyph.Cluster, i-1, glyphs[i-1].Cluster)
+		}
+	}
+	return nil
+}
+
+// shapedAdvance returns how far the cursor moves past a shaped glyph
+func (font *Font) shapedAdvance(glyph ShapedGlyph) int {
+	if glyph.HasAdvance && glyph.Glyph != ligatureTail {
+		return glyph.Advance
+	}
+	adv, _ := font.advance(glyph.Glyph)
+	return adv
+}
@@ -0,0 +1,55 @@
+package font
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShaperContractViolations(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		glyphs []ShapedGlyph
+		want   string
+	}{
+		{"no glyphs", nil, "no glyphs"},
+		{"first cluster not 0", []ShapedGlyph{{Glyph: 'a', Cluster: 1}}, "first glyph has cluster 1"},
+		{"negative cluster", []ShapedGlyph{{Glyph: 'a', Cluster: -1}}, "first glyph has cluster -1"},
+		{"decreasing clusters", []ShapedGlyph{{Glyph: 'a'}, {Glyph: 'b', Cluster: 2}, {Glyph: 'c', Cluster: 1}}, "glyph 2 has cluster 1"},
+		{"repeated cluster", []ShapedGlyph{{Glyph: 'a'}, {Glyph: 'b'}}, "glyph 1 has cluster 0"},
+		{"cluster past the end", []ShapedGlyph{{Glyph: 'a'}, {Glyph: 'b', Cluster: 3}}, "past the end of 3 runes"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			font := MakeDefaultFont()
+			font.SetShaper(func([]rune) []ShapedGlyph { return test.glyphs })
+
+			layout, err := font.Layout("abc", LayoutOptions{})
+			if err == nil {
+				t.Fatalf("Layout gave a %dx%d layout, want an error", layout.width, layout.height)
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Layout error %q doesn't mention %q", err, test.want)
+			}
+
+			// what can't return an error draws the text unshaped
+			unshaped := MakeDefaultFont()
+			if w, h := font.Measure("abc"); w != unshaped.getStringLen("abc") || h != unshaped.CharSize[1] {
+				t.Errorf("Measure = %dx%d, want the unshaped text's size", w, h)
+			}
+			assertSamePixels(t, "RenderString", font.RenderString("abc", 1, 1, 1), unshaped.RenderString("abc", 1, 1, 1))
+		})
+	}
+}
+
+func TestShaperClusters(t *testing.T) {
+	font := MakeDefaultFont()
+	// draw "ab" as one glyph, a
+	font.SetShaper(func(runes []rune) []ShapedGlyph {
+		return []ShapedGlyph{{Glyph: 'a'}, {Glyph: 'c', Cluster: 2}}
+	})
+	layout, err := font.Layout("abc", LayoutOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := MakeDefaultFont()
+	assertSamePixels(t, "shaped", layout.Render(1, 1, 1), plain.RenderString("ac", 1, 1, 1))
+}
@@ -0,0 +1,151 @@
+package font
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// 3x5 hex digits for labelling a charset sheet when the font can't, each
+// row's bits left to right from 4 to 1
+var sheetDigits = [16][5]uint8{
+	{7, 5, 5, 5, 7}, {2, 6, 2, 2, 7}, {7, 1, 7, 4, 7}, {7, 1, 7, 1, 7},
+	{5, 5, 7, 1, 1}, {7, 4, 7, 1, 7}, {7, 4, 7, 5, 7}, {7, 1, 1, 1, 1},
+	{7, 5, 7, 5, 7}, {7, 5, 7, 1, 7}, {2, 5, 7, 5, 5}, {6, 5, 6, 5, 6},
+	{3, 4, 4, 4, 3}, {6, 5, 5, 5, 6}, {7, 4, 6, 4, 7}, {7, 4, 6, 4, 4},
+}
+
+// space around and between the parts of each charset sheet entry
+const sheetPad = 2
+
+// RenderCharsetSheet draws every character of the charset in a grid of
+// columns entries, for checking a font's atlas and metrics at a glance. Each
+// entry shows the character's whole atlas cell at 1x and, when scale is more
+// than 1, enlarged that many times, each in a DebugLineColor box with its
+// width marked along the bottom in DebugGlyphColor, so ink past the width
+// and glyphs out of place in their cells stand out. At a scale SmoothScales
+// generated an atlas for, the enlarged copy is drawn from it, which holds
+// only each glyph's own rect, so ink past the width shows at 1x alone. Under
+// them is the character's code point in hex, drawn with the font if it has
+// the digits and with built in ones if it doesn't. Characters that can't be
+// drawn get an empty box.
+func (font *Font) RenderCharsetSheet(columns, scale int) (*sdl.Surface, error) {
+	if columns <= 0 {
+		return nil, fmt.Errorf("RenderCharsetSheet: %d columns", columns)
+	}
+	scale = max(scale, 1)
+
+	// what each entry shows: the whole cell of a grid glyph, and its width
+	type entry struct {
+		char  rune
+		src   sdl.Rect
+		top   int
+		width int
+		ok    bool
+	}
+	var entries []entry
+	cellW, cellH := font.CharSize[0], font.CharSize[1]
+	for _, char := range font.CharSet {
+		e := entry{char: char}
+		if rect, err := font.glyphRect(char); err == nil {
+			e.src, e.width, e.top, e.ok = rect, int(rect.W), font.glyphTop(char), true
+			if font.Rects == nil {
+				e.src.W = int32(font.CharSize[0])
+				if font.DoubleWidth[char] {
+					e.src.W = int32(2*font.CharSize[0] + font.cellPad())
+				}
+				e.src.W = min(e.src.W, font.Atlas.W-e.src.X)
+			}
+		}
+		cellW, cellH = max(cellW, int(e.src.W)), max(cellH, e.top+int(e.src.H))
+		entries = append(entries, e)
+	}
+
+	hexLabels := len(font.MissingRunes("0123456789ABCDEF")) == 0
+	labelH := 5
+	if hexLabels {
+		labelH = font.CharSize[1]
+	}
+	label := func(char rune) string { return fmt.Sprintf("%04X", char) }
+
+	boxesW := cellW + 2
+	if scale > 1 {
+		boxesW += sheetPad + cellW*scale + 2
+	}
+	entryW := boxesW
+	for _, e := range entries {
+		if hexLabels {
+			w, _ := font.Measure(label(e.char))
+			entryW = max(entryW, w)
+		} else {
+			entryW = max(entryW, 4*len(label(e.char))-1)
+		}
+	}
+	entryW += 2 * sheetPad
+	boxesH := cellH*scale + 2
+	entryH := boxesH + sheetPad + labelH + 2*sheetPad
+	sheetW := entryW * min(columns, max(len(entries), 1))
+	sheetH := entryH * max((len(entries)+columns-1)/columns, 1)
+	if sheetW > MaxScaledSize || sheetH > MaxScaledSize {
+		return nil, fmt.Errorf("RenderCharsetSheet: the sheet would be %dx%d, bigger than %d pixels", sheetW, sheetH, MaxScaledSize)
+	}
+
+	sheet := font.outputSurface(sheetW, sheetH)
+	fill := func(rect sdl.Rect, color sdl.Color) {
+		if rect.W > 0 && rect.H > 0 {
+			sheet.FillRect(&rect, sdl.MapRGBA(sheet.Format, color.R, color.G, color.B, color.A))
+		}
+	}
+	box := func(x, y, w, h, width int) {
+		fill(sdl.Rect{X: int32(x), Y: int32(y), W: int32(w), H: 1}, DebugLineColor)
+		fill(sdl.Rect{X: int32(x), Y: int32(y + h - 1), W: int32(w), H: 1}, DebugLineColor)
+		fill(sdl.Rect{X: int32(x), Y: int32(y), W: 1, H: int32(h)}, DebugLineColor)
+		fill(sdl.Rect{X: int32(x + w - 1), Y: int32(y), W: 1, H: int32(h)}, DebugLineColor)
+		fill(sdl.Rect{X: int32(x + 1), Y: int32(y + h - 1), W: int32(width), H: 1}, DebugGlyphColor)
+	}
+
+	// cells are copied with their alpha as is, and nothing goes through the
+	// atlas's color mod
+	white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	for i, e := range entries {
+		x, y := i%columns*entryW+sheetPad, i/columns*entryH+sheetPad
+
+		box(x, y, cellW+2, cellH+2, e.width)
+		if scale > 1 {
+			box(x+cellW+2+sheetPad, y, cellW*scale+2, cellH*scale+2, e.width*scale)
+		}
+		if e.ok && e.src.W > 0 {
+			src := e.src
+			enlarge(font.Atlas, src, sheet, x+1, y+1+e.top, 1, white)
+			if scale > 1 {
+				font.enlargeGlyph(src, sheet, x+cellW+3+sheetPad, y+1+e.top*scale, scale, white)
+			}
+		}
+
+		labelY := y + boxesH + sheetPad
+		text := label(e.char)
+		if hexLabels {
+			font.layout(text, LayoutOptions{}).CompositeInto(sheet, x, labelY, 1, 1, 1)
+			continue
+		}
+		for d, digit := range text {
+			bits := sheetDigits[digitValue(digit)]
+			for row, rowBits := range bits {
+				for col := 0; col < 3; col++ {
+					if rowBits&(4>>col) != 0 {
+						fill(sdl.Rect{X: int32(x + d*4 + col), Y: int32(labelY + row), W: 1, H: 1}, white)
+					}
+				}
+			}
+		}
+	}
+	return sheet, nil
+}
+
+// digitValue returns the value of an uppercase hex digit
+func digitValue(digit rune) int {
+	if digit >= 'A' {
+		return int(digit-'A') + 10
+	}
+	return int(digit - '0')
+}
@@ -0,0 +1,38 @@
+package font
+
+import "testing"
+
+func TestCharsetSheetScale(t *testing.T) {
+	font := packedFont()
+	font.CharSet = "0"
+	sheet, err := font.RenderCharsetSheet(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the enlarged copy of the glyph, right of the actual size one, has every
+	// pixel exactly 3 by 3
+	pixels := rgba(sheet)
+	at := func(x, y int) [4]uint8 { return pixels[y*int(sheet.W)+x] }
+	x, y := sheetPad+1, sheetPad+1
+	scaledX := sheetPad + font.CharSize[0] + 3 + sheetPad
+	for row := 0; row < font.CharSize[1]*3; row++ {
+		for col := 0; col < font.CharSize[0]*3; col++ {
+			if got, want := at(scaledX+col, y+row), at(x+col/3, y+row/3); got != want {
+				t.Fatalf("scaled pixel %d,%d is %v, want %v", col, row, got, want)
+			}
+		}
+	}
+	if c := at(x, y); c != cellColors[0] {
+		t.Errorf("the glyph is drawn %v, want %v", c, cellColors[0])
+	}
+}
+
+func TestCharsetSheetLeavesAtlasAlone(t *testing.T) {
+	for _, font := range []Font{MakeDefaultFont(), packedFont()} {
+		font.Atlas.SetColorMod(10, 20, 30)
+		if _, err := font.RenderCharsetSheet(4, 2); err != nil {
+			t.Fatal(err)
+		}
+		assertColorMod(t, "RenderCharsetSheet", font.Atlas, 10, 20, 30)
+	}
+}
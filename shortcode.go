@@ -0,0 +1,59 @@
+package font
+
+import "unicode"
+
+// expandShortcodes replaces each :name: in text that's in Shortcodes and
+// whose rune the font has with that rune, and each \: with a literal colon.
+// It returns the result and the index in text where each of its runes and its
+// end start, so a substituted rune spans everything up to the next index.
+func (font *Font) expandShortcodes(text []rune) ([]rune, []int) {
+	expanded := make([]rune, 0, len(text))
+	source := make([]int, 0, len(text)+1)
+	for i := 0; i < len(text); {
+		source = append(source, i)
+		if text[i] == '\\' && i+1 < len(text) && text[i+1] == ':' {
+			expanded = append(expanded, ':')
+			i += 2
+			continue
+		}
+		if char, n, ok := font.shortcodeAt(text[i:]); ok {
+			expanded = append(expanded, char)
+			i += n
+			continue
+		}
+		expanded = append(expanded, text[i])
+		i++
+	}
+	return expanded, append(source, len(text))
+}
+
+// shortcodeAt returns the rune for a shortcode at the start of text and how
+// many runes it takes up, or false if text doesn't start with a known one
+func (font *Font) shortcodeAt(text []rune) (rune, int, bool) {
+	if text[0] != ':' {
+		return 0, 0, false
+	}
+	for end := 1; end < len(text); end++ {
+		if text[end] == ':' {
+			char, ok := font.Shortcodes[string(text[1:end])]
+			if !ok || end == 1 || font.glyphIndex(char) < 0 {
+				return 0, 0, false
+			}
+			return char, end + 1, true
+		}
+		if unicode.IsSpace(text[end]) {
+			break // names never hold whitespace
+		}
+	}
+	return 0, 0, false
+}
+
+// substitute applies the font's shortcodes to text, for callers that don't
+// need to map indices back to the text passed in
+func (font *Font) substitute(text string) string {
+	if len(font.Shortcodes) == 0 {
+		return text
+	}
+	expanded, _ := font.expandShortcodes([]rune(text))
+	return string(expanded)
+}
@@ -0,0 +1,95 @@
+package font
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// SmoothScales generates copies of the atlas enlarged 2x and 4x with the EPX
+// pixel art scaler, also known as Scale2x, which rounds off the corners of
+// diagonal strokes instead of leaving the stair steps nearest neighbour
+// scaling does. Rendering at scales 2 and 4 draws from them: RenderNumber,
+// SetDPIScale and the enlarged cells of RenderCharsetSheet. Each glyph is
+// scaled on its own, with its rect's edge repeated past it, so no glyph picks
+// up pixels from its neighbours. Call it again after changing the atlas.
+func (font *Font) SmoothScales() {
+	var rects []sdl.Rect
+	for _, char := range font.CharSet {
+		if rect, err := font.glyphRect(char); err == nil && rect.W > 0 {
+			rects = append(rects, rect)
+		}
+	}
+
+	font.ClearSmoothScales() // also drops numbers drawn from the old ones
+	x2 := epx(font.Atlas, rects)
+	for i := range rects {
+		rects[i] = sdl.Rect{X: rects[i].X * 2, Y: rects[i].Y * 2, W: rects[i].W * 2, H: rects[i].H * 2}
+	}
+	font.smoothAtlases = map[int]*sdl.Surface{2: x2, 4: epx(x2, rects)}
+}
+
+// enlargeGlyph copies the atlas pixels in src onto dst n times as big with
+// the top left corner at x, y like enlarge does, from the atlas SmoothScales
+// generated for n when there is one
+func (font *Font) enlargeGlyph(src sdl.Rect, dst *sdl.Surface, x, y, n int, tint sdl.Color) {
+	if smooth, ok := font.smoothAtlases[n]; ok {
+		scaled := sdl.Rect{X: src.X * int32(n), Y: src.Y * int32(n), W: src.W * int32(n), H: src.H * int32(n)}
+		enlarge(smooth, scaled, dst, x, y, 1, tint)
+		return
+	}
+	enlarge(font.Atlas, src, dst, x, y, n, tint)
+}
+
+// ClearSmoothScales frees the atlases SmoothScales generated, so scaled
+// rendering goes back to nearest neighbour
+func (font *Font) ClearSmoothScales() {
+	for _, atlas := range font.smoothAtlases {
+		atlas.Free()
+	}
+	font.smoothAtlases = nil
+	font.ClearNumberCache()
+}
+
+// epx returns src at twice the size, with the pixels in each of rects
+// enlarged by EPX and everything else left transparent
+func epx(src *sdl.Surface, rects []sdl.Rect) *sdl.Surface {
+	dst := newSurface(int(src.W)*2, int(src.H)*2)
+	if src.MustLock() {
+		src.Lock()
+		defer src.Unlock()
+	}
+	srcPixels, dstPixels := src.Pixels(), dst.Pixels()
+
+	for _, rect := range rects {
+		// the pixel at x, y, or the nearest one inside the rect
+		at := func(x, y int) sdl.Color {
+			x = min(max(x, int(rect.X)), int(rect.X+rect.W)-1)
+			y = min(max(y, int(rect.Y)), int(rect.Y+rect.H)-1)
+			r, g, b, a := sdl.GetRGBA(getPixel(src, srcPixels, x, y), src.Format)
+			if a == 0 {
+				return sdl.Color{} // every transparent pixel is alike, whatever its color
+			}
+			return sdl.Color{R: r, G: g, B: b, A: a}
+		}
+		for y := int(rect.Y); y < int(rect.Y+rect.H); y++ {
+			for x := int(rect.X); x < int(rect.X+rect.W); x++ {
+				p := at(x, y)
+				up, left, right, down := at(x, y-1), at(x-1, y), at(x+1, y), at(x, y+1)
+				out := [4]sdl.Color{p, p, p, p} // top left, top right, bottom left, bottom right
+				if left == up && left != down && up != right {
+					out[0] = up
+				}
+				if up == right && up != left && right != down {
+					out[1] = right
+				}
+				if down == left && down != right && left != up {
+					out[2] = left
+				}
+				if right == down && right != up && down != left {
+					out[3] = down
+				}
+				for i, c := range out {
+					setPixel(dst, dstPixels, x*2+i%2, y*2+i/2, sdl.MapRGBA(dst.Format, c.R, c.G, c.B, c.A))
+				}
+			}
+		}
+	}
+	return dst
+}
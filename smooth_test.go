@@ -0,0 +1,137 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// pixelArtFont returns a font whose glyphs are drawn from rows of # for
+// opaque white and . for transparent, packed side by side in one row
+func pixelArtFont(charSet string, glyphs ...[]string) Font {
+	w, h := len(glyphs[0][0]), len(glyphs[0])
+	atlas := newSurface(w*len(glyphs), h)
+	pixels := atlas.Pixels()
+	for i, glyph := range glyphs {
+		for y, row := range glyph {
+			for x, c := range row {
+				if c == '#' {
+					setPixel(atlas, pixels, i*w+x, y, sdl.MapRGBA(atlas.Format, 255, 255, 255, 255))
+				}
+			}
+		}
+	}
+	widths := make([]int, len(glyphs))
+	for i := range widths {
+		widths[i] = w
+	}
+	return Font{Atlas: atlas, GridWidth: len(glyphs), CharSize: [2]int{w, h}, Packed: true, CharSet: charSet, CharWidths: widths}
+}
+
+// assertPixelArt fails the test unless the rect of surface is drawn as want,
+// in the rows of # and . pixelArtFont takes
+func assertPixelArt(t *testing.T, name string, surface *sdl.Surface, rect sdl.Rect, want []string) {
+	t.Helper()
+	pixels := rgba(surface)
+	for y, row := range want {
+		got := []byte(row)
+		for x := range row {
+			got[x] = '.'
+			if pixels[(int(rect.Y)+y)*int(surface.W)+int(rect.X)+x][3] > 0 {
+				got[x] = '#'
+			}
+		}
+		if string(got) != row {
+			t.Errorf("%s: row %d is %s, want %s", name, y, got, row)
+		}
+	}
+}
+
+func TestSmoothScalesGolden(t *testing.T) {
+	// 7 is a diagonal and 1 a plus, for RenderNumber to draw; the diagonal's
+	// right edge meets the plus, so bleeding would show
+	font := pixelArtFont("71", []string{
+		"#..",
+		".#.",
+		"..#",
+	}, []string{
+		".#.",
+		"###",
+		".#.",
+	})
+	font.SmoothScales()
+	defer font.ClearSmoothScales()
+
+	x2, x4 := font.smoothAtlases[2], font.smoothAtlases[4]
+	if x2 == nil || x4 == nil {
+		t.Fatal("SmoothScales made no 2x or 4x atlas")
+	}
+	assertPixelArt(t, "7 at 2x", x2, sdl.Rect{X: 0, Y: 0}, []string{
+		"##....",
+		"#.#...",
+		".###..",
+		"..###.",
+		"...#.#",
+		"....##",
+	})
+	assertPixelArt(t, "1 at 2x", x2, sdl.Rect{X: 6, Y: 0}, []string{
+		"..##..",
+		".####.",
+		"######",
+		"######",
+		".####.",
+		"..##..",
+	})
+	assertPixelArt(t, "7 at 4x", x4, sdl.Rect{X: 0, Y: 0}, []string{
+		"####........",
+		"###.#.......",
+		"##..##......",
+		"#...###.....",
+		".######.....",
+		"..#######...",
+		"...#######..",
+		".....######.",
+		".....###...#",
+		"......##..##",
+		".......#.###",
+		"........####",
+	})
+
+	// RenderNumber draws from the smoothed atlas at scale 2
+	surface, err := font.RenderNumber("1", 2, 1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPixelArt(t, "RenderNumber", surface, sdl.Rect{}, []string{
+		"..##..",
+		".####.",
+		"######",
+		"######",
+		".####.",
+		"..##..",
+	})
+}
+
+func TestSmoothScalesCharsetSheet(t *testing.T) {
+	font := pixelArtFont("7", []string{
+		"#..",
+		".#.",
+		"..#",
+	})
+	font.SmoothScales()
+	defer font.ClearSmoothScales()
+	sheet, err := font.RenderCharsetSheet(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the enlarged cell, right of the actual size one, is the EPX one
+	scaled := sdl.Rect{X: int32(sheetPad + font.CharSize[0] + 3 + sheetPad), Y: sheetPad + 1}
+	assertPixelArt(t, "sheet at 2x", sheet, scaled, []string{
+		"##....",
+		"#.#...",
+		".###..",
+		"..###.",
+		"...#.#",
+		"....##",
+	})
+}
@@ -0,0 +1,34 @@
+package font
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// MissingRunes returns the runes of text the font has no glyph for, which
+// rendering skips, each once in the order they first appear. Newlines, and
+// runes the font's ControlChars policy hides, aren't missing.
+func (font *Font) MissingRunes(text string) []rune {
+	var missing []rune
+	for _, char := range font.substitute(text) {
+		if char == '\n' || slices.Contains(missing, char) {
+			continue
+		}
+		if _, ok := font.advance(char); !ok {
+			missing = append(missing, char)
+		}
+	}
+	return missing
+}
+
+// RenderStringStrict draws text like RenderString, but returns an error
+// listing the runes the font doesn't have instead of skipping them, for
+// catching charset gaps in translations
+func (font *Font) RenderStringStrict(text string, r, g, b float64) (*sdl.Surface, error) {
+	if missing := font.MissingRunes(text); len(missing) > 0 {
+		return nil, fmt.Errorf("RenderStringStrict: no glyphs for %q", missing)
+	}
+	return font.RenderString(text, r, g, b), nil
+}
@@ -0,0 +1,150 @@
+package font
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// TextureAtlas is a font's atlas uploaded to a renderer, so text can be drawn
+// straight to the render target without going through surfaces
+type TextureAtlas struct {
+	Font     *Font
+	Renderer *sdl.Renderer
+	Texture  *sdl.Texture
+
+	// UseGeometry draws each string with a single RenderGeometry call instead
+	// of one Copy per glyph. It needs SDL 2.0.18 or newer and falls back to
+	// Copy when that isn't available.
+	UseGeometry bool
+
+	geometry bool  // whether the linked SDL supports RenderGeometry
+	alpha    uint8 // opacity text is drawn with
+
+	vertices []sdl.Vertex // reused between draws
+	indices  []int32
+}
+
+// NewTextureAtlas uploads the font's atlas to renderer as a texture
+func (font *Font) NewTextureAtlas(renderer *sdl.Renderer) (*TextureAtlas, error) {
+	texture, err := renderer.CreateTextureFromSurface(font.Atlas)
+	if err != nil {
+		return nil, err
+	}
+	texture.SetBlendMode(sdl.BLENDMODE_BLEND)
+
+	var version sdl.Version
+	sdl.GetVersion(&version)
+	geometry := sdl.VERSIONNUM(int(version.Major), int(version.Minor), int(version.Patch)) >= sdl.VERSIONNUM(2, 0, 18)
+
+	return &TextureAtlas{Font: font, Renderer: renderer, Texture: texture, geometry: geometry, alpha: 255}, nil
+}
+
+// SetAlpha sets the opacity, from 0 to 1, that text is drawn with from then on
+func (atlas *TextureAtlas) SetAlpha(a float64) {
+	atlas.alpha = uint8(min(max(a, 0), 1) * 255)
+}
+
+// DrawString draws text to the renderer's target the way RenderString draws
+// it to a surface, with the text origin at x, y
+func (atlas *TextureAtlas) DrawString(text string, x, y int, r, g, b float64) error {
+	return atlas.DrawLayout(atlas.Font.layout(text, LayoutOptions{}), x, y, r, g, b)
+}
+
+// Draw lays out text and draws it to the renderer's target with the text
+// origin at x, y
+func (atlas *TextureAtlas) Draw(text string, opts LayoutOptions, x, y int, r, g, b float64) error {
+	layout, err := atlas.Font.Layout(text, opts)
+	if err != nil {
+		return err
+	}
+	return atlas.DrawLayout(layout, x, y, r, g, b)
+}
+
+// DrawLayout draws text that has already been laid out to the renderer's
+// target with the text origin at x, y
+func (atlas *TextureAtlas) DrawLayout(layout *Layout, x, y int, r, g, b float64) error {
+	defer atlas.clipToCap(layout, x)()
+	if atlas.UseGeometry && atlas.geometry {
+		if err := atlas.drawGeometry(layout, x, y, r, g, b); err == nil {
+			return nil
+		}
+		// the renderer can't do geometry after all, so stop trying
+		atlas.geometry = false
+	}
+
+	// go-sdl2 can't read a texture's color mod back, so it's restored to the
+	// white NewTextureAtlas leaves it, which drawGeometry relies on
+	atlas.Texture.SetColorMod(uint8(r*255), uint8(g*255), uint8(b*255))
+	defer atlas.Texture.SetColorMod(255, 255, 255)
+	if alpha, err := atlas.Texture.GetAlphaMod(); err == nil {
+		defer atlas.Texture.SetAlphaMod(alpha)
+	}
+	atlas.Texture.SetAlphaMod(atlas.alpha)
+	var err error
+	layout.Walk(func(glyph GlyphPlacement) bool {
+		dst := glyph.Dst
+		dst.X += int32(x)
+		dst.Y += int32(y)
+		err = atlas.Renderer.Copy(atlas.Texture, &glyph.Src, &dst)
+		return err == nil
+	})
+	return err
+}
+
+// clipToCap narrows the renderer's clip rect to end the layout's
+// opts.MaxSurfaceWidth pixels right of the text origin at x, like
+// Layout.clipToCap does a surface's, returning a func that puts it back
+func (atlas *TextureAtlas) clipToCap(layout *Layout, x int) (restore func()) {
+	if layout.opts.MaxSurfaceWidth <= 0 {
+		return func() {}
+	}
+	renderer := atlas.Renderer
+	viewport := renderer.GetViewport()
+	clip, clipped := sdl.Rect{W: viewport.W, H: viewport.H}, renderer.IsClipEnabled()
+	if clipped {
+		clip = renderer.GetClipRect()
+	}
+	capped := clip
+	capped.W = max(min(capped.W, int32(x+layout.opts.MaxSurfaceWidth)-capped.X), 0)
+	renderer.SetClipRect(&capped)
+	return func() {
+		if clipped {
+			renderer.SetClipRect(&clip)
+		} else {
+			renderer.SetClipRect(nil)
+		}
+	}
+}
+
+// drawGeometry batches every glyph of the layout into one RenderGeometry call
+func (atlas *TextureAtlas) drawGeometry(layout *Layout, x, y int, r, g, b float64) error {
+	if len(layout.glyphs) == 0 {
+		return nil
+	}
+
+	color := sdl.Color{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: atlas.alpha}
+	atlasW, atlasH := float32(atlas.Font.Atlas.W), float32(atlas.Font.Atlas.H)
+	atlas.vertices = atlas.vertices[:0]
+	atlas.indices = atlas.indices[:0]
+
+	layout.Walk(func(glyph GlyphPlacement) bool {
+		left, top := float32(int(glyph.Dst.X)+x), float32(int(glyph.Dst.Y)+y)
+		right, bottom := left+float32(glyph.Dst.W), top+float32(glyph.Dst.H)
+		u0, v0 := float32(glyph.Src.X)/atlasW, float32(glyph.Src.Y)/atlasH
+		u1, v1 := float32(glyph.Src.X+glyph.Src.W)/atlasW, float32(glyph.Src.Y+glyph.Src.H)/atlasH
+
+		first := int32(len(atlas.vertices))
+		atlas.vertices = append(atlas.vertices,
+			sdl.Vertex{Position: sdl.FPoint{X: left, Y: top}, Color: color, TexCoord: sdl.FPoint{X: u0, Y: v0}},
+			sdl.Vertex{Position: sdl.FPoint{X: right, Y: top}, Color: color, TexCoord: sdl.FPoint{X: u1, Y: v0}},
+			sdl.Vertex{Position: sdl.FPoint{X: right, Y: bottom}, Color: color, TexCoord: sdl.FPoint{X: u1, Y: v1}},
+			sdl.Vertex{Position: sdl.FPoint{X: left, Y: bottom}, Color: color, TexCoord: sdl.FPoint{X: u0, Y: v1}},
+		)
+		atlas.indices = append(atlas.indices, first, first+1, first+2, first, first+2, first+3)
+		return true
+	})
+
+	return atlas.Renderer.RenderGeometry(atlas.Texture, atlas.vertices, atlas.indices)
+}
+
+// Destroy frees the texture
+func (atlas *TextureAtlas) Destroy() {
+	atlas.Texture.Destroy()
+}
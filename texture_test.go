@@ -0,0 +1,109 @@
+package font
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// drawTexture draws text with a TextureAtlas through a software renderer
+// onto a new surface the size of its layout
+func drawTexture(t *testing.T, font *Font, layout *Layout, geometry bool, alpha float64) (*sdl.Surface, *TextureAtlas) {
+	t.Helper()
+	w, h := layout.Size()
+	target := newSurface(w, h)
+	renderer, err := sdl.CreateSoftwareRenderer(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { renderer.Destroy() })
+	atlas, err := font.NewTextureAtlas(renderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(atlas.Destroy)
+	atlas.UseGeometry = geometry
+	atlas.SetAlpha(alpha)
+	if err := atlas.DrawLayout(layout, 0, 0, 1, 0.5, 0.25); err != nil {
+		t.Fatal(err)
+	}
+	renderer.Present()
+	return target, atlas
+}
+
+func TestTextureAtlasGeometry(t *testing.T) {
+	font := MakeDefaultFont()
+	width, _ := font.Measure("Textured glyphs")
+	layout, err := font.Layout("Textured glyphs in\none batch", LayoutOptions{MaxWidth: width, Align: AlignCenter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, alpha := range []float64{1, 0.5} {
+		copied, _ := drawTexture(t, &font, layout, false, alpha)
+		if alpha == 1 {
+			want, got := rgba(layout.Render(1, 0.5, 0.25)), rgba(copied)
+			for i := range want {
+				if !near(got[i], want[i]) {
+					w, _ := layout.Size()
+					t.Fatalf("texture pixel %d,%d is %v, Render draws %v", i%w, i/w, got[i], want[i])
+				}
+			}
+		}
+		batched, atlas := drawTexture(t, &font, layout, true, alpha)
+		if !atlas.geometry {
+			t.Log("SDL is older than 2.0.18, so this only tests falling back to Copy")
+		}
+		// one RenderGeometry call lands every glyph where a Copy per glyph does
+		want, got := rgba(copied), rgba(batched)
+		for i := range want {
+			if !near(got[i], want[i]) {
+				w, _ := layout.Size()
+				t.Fatalf("alpha %v: geometry pixel %d,%d is %v, Copy draws %v", alpha, i%w, i/w, got[i], want[i])
+			}
+		}
+	}
+}
+
+// benchmarkTextureAtlas draws a frame of 2,000 glyphs, 40 lines of 50, per iteration
+func benchmarkTextureAtlas(b *testing.B, geometry bool) {
+	font := MakeDefaultFont()
+	line := strings.Repeat("0123456789", 5)
+	layout, err := font.Layout(strings.TrimSuffix(strings.Repeat(line+"\n", 40), "\n"), LayoutOptions{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	if len(layout.glyphs) != 2000 {
+		b.Fatalf("%d glyphs laid out, want 2000", len(layout.glyphs))
+	}
+	w, h := layout.Size()
+	target := newSurface(w, h)
+	defer target.Free()
+	renderer, err := sdl.CreateSoftwareRenderer(target)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer renderer.Destroy()
+	atlas, err := font.NewTextureAtlas(renderer)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer atlas.Destroy()
+	atlas.UseGeometry = geometry
+	if geometry && !atlas.geometry {
+		b.Skip("SDL is older than 2.0.18, so there's no RenderGeometry to batch with")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		renderer.Clear()
+		if err := atlas.DrawLayout(layout, 0, 0, 1, float64(i%2), 1); err != nil {
+			b.Fatal(err)
+		}
+		renderer.Present()
+	}
+}
+
+func BenchmarkTextureAtlasGeometry(b *testing.B) { benchmarkTextureAtlas(b, true) }
+
+func BenchmarkTextureAtlasCopy(b *testing.B) { benchmarkTextureAtlas(b, false) }
@@ -0,0 +1,162 @@
+package font
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// DefaultGlyphPattern matches TexturePacker frames named like glyph_A.png
+var DefaultGlyphPattern = regexp.MustCompile(`^glyph_(.+)\.png$`)
+
+// packerRect is a rect as TexturePacker writes it
+type packerRect struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+	W int32 `json:"w"`
+	H int32 `json:"h"`
+}
+
+type packerFrame struct {
+	Filename         string     `json:"filename"` // only in the array format, the hash format keys frames by it
+	Frame            packerRect `json:"frame"`
+	Rotated          bool       `json:"rotated"`
+	Trimmed          bool       `json:"trimmed"`
+	SpriteSourceSize packerRect `json:"spriteSourceSize"` // where the trimmed frame sits in the original sprite
+	SourceSize       packerRect `json:"sourceSize"`       // the original sprite's size
+}
+
+type packerSheet struct {
+	Frames json.RawMessage `json:"frames"` // an object keyed by name, or an array
+	Meta   struct {
+		Image string `json:"image"`
+	} `json:"meta"`
+}
+
+// glyphRune reads the rune a frame name's glyph part stands for, either the
+// rune itself or its code point written like U+0041
+func glyphRune(name string) (rune, error) {
+	if utf8.RuneCountInString(name) == 1 {
+		char, _ := utf8.DecodeRuneInString(name)
+		return char, nil
+	}
+	if hex, ok := strings.CutPrefix(strings.ToUpper(name), "U+"); ok {
+		code, err := strconv.ParseUint(hex, 16, 32)
+		if err == nil && utf8.ValidRune(rune(code)) {
+			return rune(code), nil
+		}
+	}
+	return 0, fmt.Errorf("%q is neither a single character nor a code point like U+0041", name)
+}
+
+// LoadTexturePacker creates a Font from the glyphs of a TexturePacker sheet,
+// described by a JSON file in either the hash or the array format. Frames
+// whose names match pattern are glyphs, and the pattern's first submatch is
+// the glyph's character or its code point like U+0041. Other frames are left
+// out, so glyphs can share a sheet with other sprites. pattern can be nil to
+// use DefaultGlyphPattern.
+//
+// Each glyph's untrimmed sprite is its cell: trimmed frames are drawn offset
+// by the space that was trimmed off, and every glyph advances by its sprite's
+// width plus LetterPad. The charset is ordered by code point, and the cell
+// size is the largest sprite's. Rotated frames aren't supported.
+func LoadTexturePacker(path string, pattern *regexp.Regexp) (Font, error) {
+	if pattern == nil {
+		pattern = DefaultGlyphPattern
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Font{}, err
+	}
+	var sheet packerSheet
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		return Font{}, fmt.Errorf("texture packer sheet %s: %w", path, err)
+	}
+
+	var frames []packerFrame
+	var hash map[string]packerFrame
+	if err := json.Unmarshal(sheet.Frames, &hash); err == nil {
+		for name, frame := range hash {
+			frame.Filename = name
+			frames = append(frames, frame)
+		}
+	} else if err := json.Unmarshal(sheet.Frames, &frames); err != nil {
+		return Font{}, fmt.Errorf("texture packer sheet %s: frames are neither a hash nor an array: %w", path, err)
+	}
+
+	type packedGlyph struct {
+		char  rune
+		frame packerFrame
+	}
+	var glyphs []packedGlyph
+	for _, frame := range frames {
+		match := pattern.FindStringSubmatch(frame.Filename)
+		if match == nil {
+			continue
+		}
+		if len(match) < 2 {
+			return Font{}, fmt.Errorf("texture packer sheet %s: pattern %s has no submatch for the glyph", path, pattern)
+		}
+		char, err := glyphRune(match[1])
+		if err != nil {
+			return Font{}, fmt.Errorf("texture packer sheet %s: frame %q: %w", path, frame.Filename, err)
+		}
+		if frame.Rotated {
+			return Font{}, fmt.Errorf("texture packer sheet %s: frame %q is rotated, which isn't supported; disable rotation when packing", path, frame.Filename)
+		}
+		glyphs = append(glyphs, packedGlyph{char, frame})
+	}
+	if len(glyphs) == 0 {
+		return Font{}, fmt.Errorf("texture packer sheet %s: no frames match %s", path, pattern)
+	}
+	slices.SortFunc(glyphs, func(a, b packedGlyph) int { return int(a.char - b.char) })
+	for i := 1; i < len(glyphs); i++ {
+		if glyphs[i].char == glyphs[i-1].char {
+			return Font{}, fmt.Errorf("texture packer sheet %s: more than one frame for %q", path, glyphs[i].char)
+		}
+	}
+
+	atlas, err := img.Load(filepath.Join(filepath.Dir(path), sheet.Meta.Image))
+	if err != nil {
+		return Font{}, err
+	}
+
+	var charSet strings.Builder
+	font := fontFromAtlas(atlas, 1, "", nil)
+	font.CharSize = [2]int{}
+	font.Rects = make([]GlyphSource, len(glyphs))
+	for i, glyph := range glyphs {
+		charSet.WriteRune(glyph.char)
+
+		frame := glyph.frame
+		width, height := frame.SourceSize.W, frame.SourceSize.H
+		if !frame.Trimmed {
+			frame.SpriteSourceSize = packerRect{W: frame.Frame.W, H: frame.Frame.H}
+			width, height = frame.Frame.W, frame.Frame.H
+		}
+		font.Rects[i] = GlyphSource{
+			Rect:    sdl.Rect{X: frame.Frame.X, Y: frame.Frame.Y, W: frame.Frame.W, H: frame.Frame.H},
+			Advance: int(width) + font.LetterPad,
+			Top:     int(frame.SpriteSourceSize.Y),
+		}
+		if frame.SpriteSourceSize.X != 0 {
+			if font.Bearings == nil {
+				font.Bearings = make([]int, len(glyphs))
+			}
+			font.Bearings[i] = int(frame.SpriteSourceSize.X)
+		}
+		font.CharSize = [2]int{max(font.CharSize[0], int(width)), max(font.CharSize[1], int(height))}
+	}
+	font.CharSet = charSet.String()
+	return font, nil
+}
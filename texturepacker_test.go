@@ -0,0 +1,111 @@
+package font
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// packerFixture is a sheet with an untrimmed A, a B trimmed to the 3x4 of
+// ink in its 6x8 sprite, a C named by code point and a sprite that isn't a
+// glyph, in the hash format and the array format
+var packerFixture = map[string]string{
+	"hash": `{
+		"frames": {
+			"glyph_A.png": {"frame": {"x": 0, "y": 0, "w": 4, "h": 6}, "sourceSize": {"w": 4, "h": 6}},
+			"glyph_B.png": {"frame": {"x": 5, "y": 0, "w": 3, "h": 4}, "trimmed": true,
+				"spriteSourceSize": {"x": 2, "y": 3, "w": 3, "h": 4}, "sourceSize": {"w": 6, "h": 8}},
+			"glyph_U+0043.png": {"frame": {"x": 10, "y": 0, "w": 2, "h": 6}, "sourceSize": {"w": 2, "h": 6}},
+			"button.png": {"frame": {"x": 14, "y": 0, "w": 6, "h": 10}, "sourceSize": {"w": 6, "h": 10}}
+		},
+		"meta": {"image": "sheet.png"}
+	}`,
+	"array": `{
+		"frames": [
+			{"filename": "button.png", "frame": {"x": 14, "y": 0, "w": 6, "h": 10}, "sourceSize": {"w": 6, "h": 10}},
+			{"filename": "glyph_U+0043.png", "frame": {"x": 10, "y": 0, "w": 2, "h": 6}, "sourceSize": {"w": 2, "h": 6}},
+			{"filename": "glyph_B.png", "frame": {"x": 5, "y": 0, "w": 3, "h": 4}, "trimmed": true,
+				"spriteSourceSize": {"x": 2, "y": 3, "w": 3, "h": 4}, "sourceSize": {"w": 6, "h": 8}},
+			{"filename": "glyph_A.png", "frame": {"x": 0, "y": 0, "w": 4, "h": 6}, "sourceSize": {"w": 4, "h": 6}}
+		],
+		"meta": {"image": "sheet.png"}
+	}`,
+}
+
+func TestLoadTexturePacker(t *testing.T) {
+	dir := t.TempDir()
+	sheet := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	for _, frame := range []image.Rectangle{image.Rect(0, 0, 4, 6), image.Rect(5, 0, 8, 4), image.Rect(10, 0, 12, 6), image.Rect(14, 0, 20, 10)} {
+		for y := frame.Min.Y; y < frame.Max.Y; y++ {
+			for x := frame.Min.X; x < frame.Max.X; x++ {
+				sheet.SetNRGBA(x, y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+			}
+		}
+	}
+	writePNG(t, filepath.Join(dir, "sheet.png"), sheet)
+
+	for format, def := range packerFixture {
+		t.Run(format, func(t *testing.T) {
+			path := filepath.Join(dir, format+".json")
+			if err := os.WriteFile(path, []byte(def), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			font, err := LoadTexturePacker(path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if font.CharSet != "ABC" || font.CharSize != [2]int{6, 8} {
+				t.Errorf("charset %q with %v cells, want \"ABC\" with the largest sprite's 6x8", font.CharSet, font.CharSize)
+			}
+
+			// each glyph advances by its untrimmed sprite's width
+			for _, test := range []struct {
+				char  rune
+				width int
+			}{
+				{'A', 4}, {'B', 6}, {'C', 2},
+			} {
+				if w, _ := font.Measure(string(test.char)); w != test.width+font.LetterPad {
+					t.Errorf("Measure(%q) = %d, want %d", test.char, w, test.width+font.LetterPad)
+				}
+			}
+
+			// the trimmed B's ink sits where it was in its sprite
+			surface := font.RenderString("AB", 1, 1, 1)
+			if surface.W != 4+6+2*int32(font.LetterPad) || surface.H != 8 {
+				t.Fatalf("RenderString is %dx%d", surface.W, surface.H)
+			}
+			pixels := rgba(surface)
+			bX := 4 + font.LetterPad + 2
+			for i, p := range pixels {
+				x, y := i%int(surface.W), i/int(surface.W)
+				ink := x < 4 && y < 6 || x >= bX && x < bX+3 && y >= 3 && y < 7
+				if (p[3] > 0) != ink {
+					t.Fatalf("pixel %d,%d has ink %t, want %t", x, y, p[3] > 0, ink)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTexturePackerDuplicate(t *testing.T) {
+	// two frames for A, found before the sheet's image is needed, so the
+	// missing image is never loaded
+	path := filepath.Join(t.TempDir(), "sheet.json")
+	def := `{
+		"frames": {
+			"glyph_A.png": {"frame": {"x": 0, "y": 0, "w": 4, "h": 6}, "sourceSize": {"w": 4, "h": 6}},
+			"glyph_U+0041.png": {"frame": {"x": 5, "y": 0, "w": 4, "h": 6}, "sourceSize": {"w": 4, "h": 6}}
+		},
+		"meta": {"image": "missing.png"}
+	}`
+	if err := os.WriteFile(path, []byte(def), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTexturePacker(path, nil); err == nil || !strings.Contains(err.Error(), "more than one frame") {
+		t.Errorf("LoadTexturePacker error = %v, want one about the duplicate frames", err)
+	}
+}
@@ -0,0 +1,75 @@
+package font
+
+import "strings"
+
+// collapser turns runs of whitespace into single spaces one rune at a time,
+// so strings and streams collapse the same way
+type collapser struct {
+	newlines bool // collapse newlines along with spaces and tabs
+	aliases  map[rune]rune
+	inSpace  bool
+}
+
+// next returns the rune to emit for char, or false if char is dropped
+func (c *collapser) next(char rune) (rune, bool) {
+	space := char
+	if target, ok := c.aliases[char]; ok {
+		space = target // runes aliased to spaces collapse like them
+	}
+	if isBreakSpace(space) || c.newlines && char == '\n' {
+		if c.inSpace {
+			return 0, false
+		}
+		c.inSpace = true
+		return ' ', true
+	}
+	c.inSpace = false
+	return char, true
+}
+
+// collapseSource collapses runs of spaces and tabs in text into single spaces
+// for a layout, along with newlines when the font collapses those, returning
+// the result and the index in text of each of its runes and its end. With
+// trim, spaces next to newlines and at either end are dropped too.
+func (font *Font) collapseSource(text []rune, trim bool) (string, []int) {
+	c := collapser{newlines: font.CollapseWhitespace && font.CollapseNewlines, aliases: font.Aliases}
+	collapsed := make([]rune, 0, len(text))
+	source := make([]int, 0, len(text)+1)
+	for i, char := range text {
+		if char, ok := c.next(char); ok {
+			collapsed = append(collapsed, char)
+			source = append(source, i)
+		}
+	}
+
+	if trim {
+		kept := 0
+		for i, char := range collapsed {
+			if char == ' ' && (i == 0 || i == len(collapsed)-1 || collapsed[i-1] == '\n' || collapsed[i+1] == '\n') {
+				continue
+			}
+			collapsed[kept], source[kept] = char, source[i]
+			kept++
+		}
+		collapsed, source = collapsed[:kept], source[:kept]
+	}
+
+	return string(collapsed), append(source, len(text))
+}
+
+// normalize applies the font's whitespace collapsing to text before layout
+func (font *Font) normalize(text string) string {
+	if !font.CollapseWhitespace {
+		return text
+	}
+
+	c := collapser{newlines: font.CollapseNewlines, aliases: font.Aliases}
+	var collapsed strings.Builder
+	collapsed.Grow(len(text))
+	for _, char := range text {
+		if char, ok := c.next(char); ok {
+			collapsed.WriteRune(char)
+		}
+	}
+	return collapsed.String()
+}
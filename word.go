@@ -0,0 +1,71 @@
+package font
+
+import "unicode"
+
+// character classes used to find word boundaries
+const (
+	classSpace = iota // the same spaces the wrapper breaks at, plus newlines
+	classWord         // letters, digits, underscores and combining marks
+	classPunct        // everything else, so "well-known" is "well", "-", "known" like the wrapper sees it
+)
+
+func runeClass(char rune) int {
+	switch {
+	case isBreakSpace(char) || char == '\n':
+		return classSpace
+	case unicode.IsLetter(char) || unicode.IsDigit(char) || unicode.IsMark(char) || char == '_':
+		return classWord
+	default:
+		return classPunct
+	}
+}
+
+// WordAt returns the rune range [start, end) of the run of same-class runes
+// (word, punctuation or whitespace) at index. An index at the end of the text
+// selects the run before it.
+func WordAt(text string, index int) (start, end int) {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return 0, 0
+	}
+	index = min(max(index, 0), len(runes)-1)
+
+	class := runeClass(runes[index])
+	start, end = index, index+1
+	for start > 0 && runeClass(runes[start-1]) == class {
+		start--
+	}
+	for end < len(runes) && runeClass(runes[end]) == class {
+		end++
+	}
+	return
+}
+
+// NextWordStart returns the rune index of the start of the word after index,
+// or the length of the text if there isn't one
+func NextWordStart(text string, index int) int {
+	runes := []rune(text)
+	index = min(max(index, 0), len(runes))
+	if index < len(runes) && runeClass(runes[index]) != classSpace {
+		_, index = WordAt(text, index)
+	}
+	for index < len(runes) && runeClass(runes[index]) == classSpace {
+		index++
+	}
+	return index
+}
+
+// PrevWordStart returns the rune index of the start of the word before index,
+// or 0 if there isn't one
+func PrevWordStart(text string, index int) int {
+	runes := []rune(text)
+	index = min(max(index, 0), len(runes))
+	for index > 0 && runeClass(runes[index-1]) == classSpace {
+		index--
+	}
+	if index == 0 {
+		return 0
+	}
+	start, _ := WordAt(text, index-1)
+	return start
+}
@@ -0,0 +1,421 @@
+package font
+
+import (
+	"io"
+	"slices"
+)
+
+// BreakKind describes how a wrapped line ended
+type BreakKind int
+
+const (
+	BreakEnd     BreakKind = iota // last line of the text
+	BreakNewline                  // a '\n' was consumed
+	BreakSpace                    // a run of spaces/tabs was consumed
+	BreakHyphen                   // a word was split and a hyphen was inserted
+	BreakSplit                    // broke between two runes without consuming or inserting anything
+)
+
+// number of space advances a tab takes up when the font doesn't set TabSpaces
+const tabSpaces = 4
+
+// WrappedLine is one line of wrapped text
+type WrappedLine struct {
+	Start, End int // rune range [Start, End) of the original text drawn on this line
+	Break      BreakKind
+	Width      int // pixel width of the line, including an inserted hyphen
+}
+
+// WrapResult is text broken into lines that fit within MaxWidth
+type WrapResult struct {
+	Text     []rune
+	MaxWidth int
+	Lines    []WrappedLine
+
+	glyphs      []ShapedGlyph // what's drawn for each rune of Text, after shaping
+	firstIndent int           // first line indent lines were wrapped with, negative for a hanging indent
+	shapeErr    error         // how the font's shaper broke its contract on Text, if it did
+}
+
+// indent returns how far line is indented by the wrap's first line indent
+func (wrap *WrapResult) indent(line int) int {
+	if line == 0 || wrap.Lines[line-1].Break == BreakNewline {
+		return max(wrap.firstIndent, 0)
+	}
+	return max(-wrap.firstIndent, 0)
+}
+
+// next returns the rune index where the line after line starts
+func (wrap *WrapResult) next(line int) int {
+	if line+1 < len(wrap.Lines) {
+		return wrap.Lines[line+1].Start
+	}
+	return len(wrap.Text)
+}
+
+// OriginalToWrapped maps a rune index in the original text to a line and a
+// column within it. Runes consumed by a break map to the end of their line.
+func (wrap *WrapResult) OriginalToWrapped(index int) (line, col int) {
+	if index < 0 {
+		index = 0
+	}
+	for line = range wrap.Lines {
+		if index < wrap.next(line) || line == len(wrap.Lines)-1 {
+			break
+		}
+	}
+	l := wrap.Lines[line]
+	return line, min(max(index-l.Start, 0), l.End-l.Start)
+}
+
+// WrappedToOriginal maps a line and column back to a rune index in the
+// original text, clamping both to the wrapped layout
+func (wrap *WrapResult) WrappedToOriginal(line, col int) int {
+	line = min(max(line, 0), len(wrap.Lines)-1)
+	l := wrap.Lines[line]
+	return l.Start + min(max(col, 0), l.End-l.Start)
+}
+
+// LineText returns the text drawn on a line, including an inserted hyphen
+func (wrap *WrapResult) LineText(line int) string {
+	l := wrap.Lines[line]
+	text := string(wrap.Text[l.Start:l.End])
+	if l.Break == BreakHyphen {
+		text += "-"
+	}
+	return text
+}
+
+// advance returns how far the cursor moves past char, and false if the font can't draw it
+func (font *Font) advance(char rune) (int, bool) {
+	adv, ok := font.glyphAdvance(char)
+	if char == ' ' && ok {
+		adv = max(adv+font.WordSpacing, 0)
+	}
+	return adv, ok
+}
+
+// glyphAdvance does the work of advance, without WordSpacing
+func (font *Font) glyphAdvance(char rune) (int, bool) {
+	if char == ligatureTail {
+		return 0, true // the ligature's glyph advances for it
+	}
+	char, visible := font.displayRune(char)
+	if !visible {
+		return 0, true
+	}
+	if adv, ok := font.AdvanceOverride[char]; ok {
+		return adv, true
+	}
+	if _, ok := font.Combining[char]; ok {
+		return 0, font.glyphIndex(char) >= 0
+	}
+	if char == '\t' {
+		space, _ := font.advance(' ')
+		if font.TabSpaces > 0 {
+			return space * font.TabSpaces, true
+		}
+		return space * tabSpaces, true
+	}
+	index := font.glyphIndex(char)
+	if index < 0 {
+		return 0, false
+	}
+	// glyphs outside the atlas are as good as missing
+	if _, err := font.glyphRect(char); err != nil {
+		return 0, false
+	}
+	if font.Rects != nil {
+		return font.Rects[index].Advance, true
+	}
+	return font.gridAdvance(index, char), true
+}
+
+// gridAdvance returns how far the cursor moves past char, the glyph at index,
+// in a font without a rect table
+func (font *Font) gridAdvance(index int, char rune) int {
+	if index < len(font.Advances) {
+		return font.Advances[index]
+	}
+	if font.DoubleWidth[char] {
+		return 2*font.CharSize[0] + font.LetterPad
+	}
+	width, _ := font.charWidth(index)
+	return width + font.LetterPad
+}
+
+func (font *Font) isCombining(char rune) bool {
+	_, ok := font.Combining[char]
+	return ok
+}
+
+// hang returns how far rune i of runes may hang into the margin when it's at
+// the start or end of a line, from HangingPunctuation
+func (font *Font) hang(runes []rune, glyphs []ShapedGlyph, i int) int {
+	if len(font.HangingPunctuation) == 0 || glyphs[i].Glyph == ligatureTail {
+		return 0
+	}
+	return min(max(font.HangingPunctuation[font.alias(runes[i])], 0), font.shapedAdvance(glyphs[i]))
+}
+
+func isBreakSpace(char rune) bool {
+	return char == ' ' || char == '\t'
+}
+
+// Wrap breaks text into lines no wider than maxWidth pixels. Lines break at
+// runs of spaces (which are consumed), after hyphens, and inside words that
+// don't fit on a line by themselves. A maxWidth of 0 only breaks at newlines.
+// Text and indices are those of the text passed in, even where whitespace is
+// collapsed or shortcodes are substituted, so LineText is the part of it
+// drawn on a line.
+func (font *Font) Wrap(text string, maxWidth int) *WrapResult {
+	layout := font.layout(text, LayoutOptions{MaxWidth: maxWidth})
+	if layout.source == nil {
+		return layout.wrap
+	}
+	wrap := &WrapResult{Text: []rune(text), MaxWidth: maxWidth, Lines: slices.Clone(layout.wrap.Lines)}
+	for i, line := range wrap.Lines {
+		wrap.Lines[i].Start, wrap.Lines[i].End = layout.SourceIndex(line.Start), layout.SourceIndex(line.End)
+	}
+	return wrap
+}
+
+// wrap does the work of Wrap, with or without the font's ligatures, leaving
+// room for indent on the lines it applies to and balancing paragraphs of up
+// to balance lines
+func (font *Font) wrap(text string, maxWidth, indent, balance int, ligatures bool) *WrapResult {
+	wrap := &WrapResult{Text: []rune(font.normalize(text)), MaxWidth: maxWidth, firstIndent: indent}
+	runes := wrap.Text
+	wrap.glyphs, wrap.shapeErr = font.shape(runes, ligatures)
+
+	paraStart := 0
+	for paraStart <= len(runes) {
+		paraEnd := paraStart
+		for paraEnd < len(runes) && runes[paraEnd] != '\n' {
+			paraEnd++
+		}
+		kind := BreakNewline
+		if paraEnd == len(runes) {
+			kind = BreakEnd
+		}
+		first := len(wrap.Lines)
+		font.wrapParagraph(wrap, paraStart, paraEnd, kind, maxWidth)
+		if lines := len(wrap.Lines) - first; maxWidth > 0 && lines > 1 && lines <= balance {
+			font.balanceParagraph(wrap, first, paraStart, paraEnd, kind)
+		}
+		paraStart = paraEnd + 1
+	}
+	return wrap
+}
+
+// wrapParagraph greedily fills lines no wider than maxWidth from runes
+// [start, end), which hold no newlines
+func (font *Font) wrapParagraph(wrap *WrapResult, start, end int, last BreakKind, maxWidth int) {
+	wrapParagraph(fontMetrics{font, wrap.Text, wrap.glyphs}, wrap, start, end, last, maxWidth)
+}
+
+// wrapParagraph does the work of Font.wrapParagraph with any metrics
+func wrapParagraph(m lineMetrics, wrap *WrapResult, start, end int, last BreakKind, maxWidth int) {
+	for {
+		limit := maxWidth
+		if limit > 0 {
+			// an indent too wide for the line still leaves room for a rune per line
+			limit = max(limit-wrap.indent(len(wrap.Lines)), 1)
+		}
+		line, next, done := fillLine(m, start, end, limit)
+		if done {
+			line.Break = last
+		}
+		wrap.Lines = append(wrap.Lines, line)
+		if done {
+			return
+		}
+		start = next
+	}
+}
+
+// balanceParagraph rewraps the paragraph of runes [start, end), whose lines
+// greedy wrapping put from line first on, at the narrowest width that still
+// takes as many lines and splits no more words, so its lines come out as even
+// as they can instead of leaving a short last line. Lines are never wider
+// than greedy wrapping allowed, since the width only ever shrinks.
+func (font *Font) balanceParagraph(wrap *WrapResult, first, start, end int, last BreakKind) {
+	greedy := slices.Clone(wrap.Lines[first:])
+	lines, splits := len(greedy), wrap.splits(greedy)
+	fits := func(width int) bool {
+		wrap.Lines = wrap.Lines[:first]
+		font.wrapParagraph(wrap, start, end, last, width)
+		return len(wrap.Lines)-first == lines && wrap.splits(wrap.Lines[first:]) <= splits
+	}
+
+	// the narrowest width that fits, which a narrower width never does
+	// unless breaking in a different place happens to
+	low, high := 1, wrap.MaxWidth
+	for low < high {
+		if mid := (low + high) / 2; fits(mid) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	if high == wrap.MaxWidth || !fits(high) {
+		wrap.Lines = append(wrap.Lines[:first], greedy...)
+	}
+}
+
+// splits counts the lines that end inside a word, rather than at a space or
+// after a hyphen the text already has
+func (wrap *WrapResult) splits(lines []WrappedLine) (n int) {
+	for _, line := range lines {
+		if line.Break == BreakHyphen || line.Break == BreakSplit && (line.End == line.Start || wrap.Text[line.End-1] != '-') {
+			n++
+		}
+	}
+	return n
+}
+
+// lineMetrics is what fillLine needs to know about each rune of the text it
+// breaks into lines, by index
+type lineMetrics interface {
+	advance(i int) int     // how far the rune moves the cursor
+	breakSpace(i int) bool // whether it's a space or tab lines break at
+	breakAfter(i int) bool // whether a line can break after it, as after a hyphen
+	hang(i int) int        // how far it may hang into the margin
+	joined(i int) bool     // whether it continues the cluster of the rune before it
+	hyphen() (int, bool)   // the advance of an inserted hyphen, and false if there isn't one
+}
+
+// fontMetrics are a font's metrics for shaped runes
+type fontMetrics struct {
+	font   *Font
+	runes  []rune
+	glyphs []ShapedGlyph
+}
+
+func (m fontMetrics) advance(i int) int { return m.font.shapedAdvance(m.glyphs[i]) }
+
+func (m fontMetrics) breakSpace(i int) bool { return isBreakSpace(m.font.alias(m.runes[i])) }
+
+func (m fontMetrics) breakAfter(i int) bool {
+	return m.font.alias(m.runes[i]) == '-' && (i+1 >= len(m.glyphs) || m.glyphs[i+1].Glyph != ligatureTail)
+}
+
+func (m fontMetrics) hang(i int) int { return m.font.hang(m.runes, m.glyphs, i) }
+
+func (m fontMetrics) joined(i int) bool { return m.font.continuesCluster(m.runes, m.glyphs, i) }
+
+func (m fontMetrics) hyphen() (int, bool) { return m.font.advance('-') }
+
+// fillLine fits as much of runes [start, end) as possible into limit pixels,
+// returning the line, where the next one starts, and whether it reached end.
+// Lines never break inside a cluster, which includes ligatures.
+func (font *Font) fillLine(runes []rune, glyphs []ShapedGlyph, start, end, limit int) (WrappedLine, int, bool) {
+	return fillLine(fontMetrics{font, runes, glyphs}, start, end, limit)
+}
+
+// fillLine does the work of Font.fillLine with any metrics, so text measured
+// by a TextRenderer breaks in the same places
+func fillLine(m lineMetrics, start, end, limit int) (WrappedLine, int, bool) {
+	width := 0
+	if limit > 0 && start < end {
+		limit += m.hang(start) // it hangs into the margin
+	}
+	candidate, candidateNext := WrappedLine{End: -1}, 0
+
+	for i := start; i < end; {
+		if m.breakSpace(i) {
+			j, runWidth := i, 0
+			for j < end && m.breakSpace(j) {
+				runWidth += m.advance(j)
+				j++
+			}
+			if j < end {
+				candidate = WrappedLine{Start: start, End: i, Break: BreakSpace, Width: width}
+				candidateNext = j
+				if limit > 0 && width+runWidth > limit {
+					// the spaces overflow, so the next word starts a new line anyway
+					return candidate, candidateNext, false
+				}
+			}
+			width += runWidth
+			i = j
+			continue
+		}
+
+		adv := m.advance(i)
+		if limit > 0 && width+adv-m.hang(i) > limit && i > start {
+			if candidate.End >= 0 {
+				return candidate, candidateNext, false
+			}
+
+			// no break opportunity on this line, so split the word
+			if hyphen, ok := m.hyphen(); ok {
+				k, w := i, width
+				for k > start+1 && (w+hyphen > limit || m.joined(k)) {
+					k--
+					w -= m.advance(k)
+				}
+				return WrappedLine{Start: start, End: k, Break: BreakHyphen, Width: w + hyphen}, k, false
+			}
+			k := i
+			for k > start+1 && m.joined(k) {
+				k--
+				width -= m.advance(k)
+			}
+			return WrappedLine{Start: start, End: k, Break: BreakSplit, Width: width}, k, false
+		}
+
+		width += adv
+		i++
+		if i < end && m.breakAfter(i-1) {
+			candidate = WrappedLine{Start: start, End: i, Break: BreakSplit, Width: width}
+			candidateNext = i
+		}
+	}
+
+	return WrappedLine{Start: start, End: end, Width: width}, end, true
+}
+
+// MeasureReader wraps text read from r at maxWidth like Wrap, returning the
+// number of lines and the width of the widest one. Only one paragraph is held
+// in memory at a time. Reading stops at the first error, including io.EOF.
+func (font *Font) MeasureReader(r io.RuneReader, maxWidth int) (lines, maxLineWidth int) {
+	var para []rune
+	collapse := collapser{newlines: font.CollapseNewlines, aliases: font.Aliases}
+	flush := func() {
+		if len(font.Shortcodes) > 0 {
+			para, _ = font.expandShortcodes(para)
+		}
+		glyphs, _ := font.shape(para, true)
+		for start := 0; ; {
+			line, next, done := font.fillLine(para, glyphs, start, len(para), maxWidth)
+			lines++
+			maxLineWidth = max(maxLineWidth, line.Width)
+			if done {
+				break
+			}
+			start = next
+		}
+		para = para[:0]
+	}
+
+	for {
+		char, _, err := r.ReadRune()
+		if err != nil {
+			break
+		}
+		if font.CollapseWhitespace {
+			var keep bool
+			if char, keep = collapse.next(char); !keep {
+				continue
+			}
+		}
+		if char == '\n' {
+			flush()
+			continue
+		}
+		para = append(para, char)
+	}
+	flush()
+	return
+}
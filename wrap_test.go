@@ -0,0 +1,96 @@
+package font
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWrapIndicesReferToOriginal(t *testing.T) {
+	font := MakeDefaultFont()
+	font.CollapseWhitespace = true
+	font.Shortcodes = map[string]rune{"smile": '☺'}
+
+	text := "aa   bb :smile:"
+	width, _ := font.Measure("aa")
+	wrap := font.Wrap(text, width+1)
+	if string(wrap.Text) != text {
+		t.Errorf("Text is %q, want the text passed in", string(wrap.Text))
+	}
+	want := []struct {
+		start, end int
+		text       string
+	}{
+		{0, 2, "aa"}, {5, 7, "bb"}, {8, 15, ":smile:"},
+	}
+	if len(wrap.Lines) != len(want) {
+		t.Fatalf("%d lines, want %d", len(wrap.Lines), len(want))
+	}
+	for i, w := range want {
+		line := wrap.Lines[i]
+		if line.Start != w.start || line.End != w.end || wrap.LineText(i) != w.text {
+			t.Errorf("line %d is [%d, %d) %q, want [%d, %d) %q", i, line.Start, line.End, wrap.LineText(i), w.start, w.end, w.text)
+		}
+	}
+	if line, col := wrap.OriginalToWrapped(6); line != 1 || col != 1 {
+		t.Errorf("OriginalToWrapped(6) = %d, %d, want 1, 1", line, col)
+	}
+	if index := wrap.WrappedToOriginal(2, 0); index != 8 {
+		t.Errorf("WrappedToOriginal(2, 0) = %d, want 8", index)
+	}
+}
+
+func TestBalanceLines(t *testing.T) {
+	font := MakeDefaultFont()
+	for _, test := range []struct {
+		text, widest     string // widest is the longest line greedy wrapping fits
+		balance          int
+		greedy, balanced []string
+	}{
+		{
+			"The quick brown fox jumps over the lazy dog", "The quick brown fox jumps", 3,
+			[]string{"The quick brown fox jumps", "over the lazy dog"},
+			[]string{"The quick brown fox", "jumps over the lazy dog"},
+		},
+		{
+			"Are you sure you want to quit?", "Are you sure you want to", 3,
+			[]string{"Are you sure you want to", "quit?"},
+			[]string{"Are you sure you", "want to quit?"},
+		},
+		{
+			// paragraphs balance on their own, and the hard break stays
+			"Hello there\nGeneral Kenobi, you are a bold one", "General Kenobi, you are", 3,
+			[]string{"Hello there", "General Kenobi, you are", "a bold one"},
+			[]string{"Hello there", "General Kenobi,", "you are a bold one"},
+		},
+		{
+			// a paragraph of more lines than BalanceLines is wrapped greedily
+			"Are you sure you want to quit?", "Are you sure you want to", 1,
+			[]string{"Are you sure you want to", "quit?"},
+			[]string{"Are you sure you want to", "quit?"},
+		},
+	} {
+		maxWidth, _ := font.Measure(test.widest)
+		for _, strategy := range []struct {
+			balance int
+			want    []string
+		}{
+			{0, test.greedy},
+			{test.balance, test.balanced},
+		} {
+			layout, err := font.Layout(test.text, LayoutOptions{MaxWidth: maxWidth, BalanceLines: strategy.balance})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var lines []string
+			for i, line := range layout.Lines() {
+				lines = append(lines, layout.wrap.LineText(i))
+				if line.Width > maxWidth {
+					t.Errorf("BalanceLines %d: line %q is %d wide, past %d", strategy.balance, lines[i], line.Width, maxWidth)
+				}
+			}
+			if !slices.Equal(lines, strategy.want) {
+				t.Errorf("BalanceLines %d: %q wraps as %q, want %q", strategy.balance, test.text, lines, strategy.want)
+			}
+		}
+	}
+}